@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
-	"sync"
+
+	"github.com/gorilla/websocket"
 )
 
 const (
-	// DefaultRetryCount is the default number of retries.
+	// DefaultRetryCount is the default maximum number of retries performed by
+	// ExponentialBackoffRetryPolicy.
 	DefaultRetryCount = 3
 )
 
@@ -20,9 +22,16 @@ type Client struct {
 	endpoint      string
 	http          *http.Client
 	token         string
-	retryCount    int
 	tokenProvider TokenProvider
-	mu            sync.Mutex
+	retryPolicy   RetryPolicy
+
+	webSocketDialer *websocket.Dialer
+
+	enablePersistedQueries     bool
+	usePersistedQueriesOverGET bool
+	apqCache                   *queryHashCache
+
+	interceptors []RequestInterceptor
 }
 
 // TokenProvider is an interface for providing a token.
@@ -35,6 +44,12 @@ type Request struct {
 	Operation string      `json:"-"`
 	Query     string      `json:"query"`
 	Variables interface{} `json:"variables"`
+
+	// Headers are additional HTTP headers to send with this request, e.g. a per-request
+	// X-Request-ID or tenant ID. A RequestInterceptor typically populates this field;
+	// see HeaderInjector. Headers are applied after the client's own Authorization
+	// header, so an interceptor can override it if needed.
+	Headers map[string]string `json:"-"`
 }
 
 // Response is a GraphQL response.
@@ -48,6 +63,30 @@ type Options struct {
 	Endpoint      string
 	HTTPClient    *http.Client
 	TokenProvider TokenProvider
+
+	// RetryPolicy decides whether, and how long to wait before, a failed request is
+	// retried. Defaults to an ExponentialBackoffRetryPolicy.
+	RetryPolicy RetryPolicy
+
+	// WebSocketDialer is used by Subscribe to open the graphql-transport-ws
+	// connection. Defaults to websocket.DefaultDialer.
+	WebSocketDialer *websocket.Dialer
+
+	// EnablePersistedQueries switches the client to the automatic persisted queries
+	// (APQ) protocol: requests send only the query's SHA-256 hash, registering the
+	// full query with the server on a PERSISTED_QUERY_NOT_FOUND response.
+	EnablePersistedQueries bool
+
+	// UsePersistedQueriesOverGET sends already-registered persisted queries as GET
+	// requests with URL-encoded params, enabling CDN caching. Only takes effect when
+	// EnablePersistedQueries is set, and only for queries: mutations always go over
+	// POST regardless of this setting, since a GET must be side-effect free.
+	UsePersistedQueriesOverGET bool
+
+	// Interceptors wrap every Query, Mutation, QueryWithFiles, and MutationWithFiles
+	// call, in the order given: the first interceptor is outermost and runs first.
+	// See RequestInterceptor.
+	Interceptors []RequestInterceptor
 }
 
 // New creates a new GraphQL client with the specified endpoint.
@@ -55,10 +94,21 @@ func New(opts Options) *Client {
 	if opts.HTTPClient == nil {
 		opts.HTTPClient = http.DefaultClient
 	}
+	if opts.RetryPolicy == nil {
+		opts.RetryPolicy = NewExponentialBackoffRetryPolicy()
+	}
 	return &Client{
-		endpoint:      opts.Endpoint,
-		http:          opts.HTTPClient,
-		tokenProvider: opts.TokenProvider,
+		endpoint:        opts.Endpoint,
+		http:            opts.HTTPClient,
+		tokenProvider:   opts.TokenProvider,
+		retryPolicy:     opts.RetryPolicy,
+		webSocketDialer: opts.WebSocketDialer,
+
+		enablePersistedQueries:     opts.EnablePersistedQueries,
+		usePersistedQueriesOverGET: opts.UsePersistedQueriesOverGET,
+		apqCache:                   newQueryHashCache(defaultPersistedQueryCacheSize),
+
+		interceptors: opts.Interceptors,
 	}
 }
 
@@ -82,85 +132,164 @@ func (c *Client) Mutation(ctx context.Context, q string, v interface{}, resp int
 	return c.do(ctx, req, resp)
 }
 
-// do executes a GraphQL request.
+// do executes a GraphQL request through the client's interceptor chain, which terminates in
+// doTransport.
 func (c *Client) do(ctx context.Context, req *Request, data interface{}) error {
-	err := validateOperationVariables(req.Variables)
-	if err != nil {
+	if err := validateOperationVariables(req.Variables); err != nil {
 		return fmt.Errorf("failed to validate operation variables: %w", err)
 	}
 
+	terminal := func(ctx context.Context, req *Request) (*Response, error) {
+		err := c.doTransport(ctx, req, data)
+		return &Response{Data: data}, err
+	}
+
+	return c.runIntercepted(ctx, req, terminal)
+}
+
+// runIntercepted populates req.Headers with the client's own Authorization header, then
+// runs req through the client's interceptor chain, terminating in terminal. Populating
+// req.Headers before the interceptor chain runs means a RequestInterceptor (e.g.
+// LoggingInterceptor) can see and redact it; setCommonHeaders applies req.Headers last, so
+// an interceptor that overrides this entry still wins, same as any other header.
+func (c *Client) runIntercepted(ctx context.Context, req *Request, terminal func(ctx context.Context, req *Request) (*Response, error)) error {
+	if c.token != "" {
+		if _, ok := req.Headers["Authorization"]; !ok {
+			if req.Headers == nil {
+				req.Headers = map[string]string{}
+			}
+			req.Headers["Authorization"] = "Bearer " + c.token
+		}
+	}
+
+	_, err := chainInterceptors(c.interceptors, terminal)(ctx, req)
+	return err
+}
+
+// doTransport sends req over HTTP, choosing the plain JSON POST or the automatic persisted
+// queries protocol depending on how the client was configured.
+func (c *Client) doTransport(ctx context.Context, req *Request, data interface{}) error {
+	if c.enablePersistedQueries {
+		return c.doPersisted(ctx, req, data)
+	}
+
 	jsonReq, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(jsonReq))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	build := func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(jsonReq))
+		if err != nil {
+			return nil, err
+		}
+		c.setCommonHeaders(httpReq, req)
+		return httpReq, nil
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	return c.doHTTP(ctx, req, build, data)
+}
+
+// setCommonHeaders sets the headers shared by every request the client issues, followed by
+// any headers req carries (see Request.Headers).
+func (c *Client) setCommonHeaders(httpReq *http.Request, req *Request) {
 	httpReq.Header.Set("Accept", "application/json")
+	if httpReq.Method == http.MethodPost {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
 	if c.token != "" {
 		httpReq.Header.Set("Authorization", "Bearer "+c.token)
 	}
-
-	httpResp, err := c.http.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("failed to do request: %w", err)
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
 	}
-	defer httpResp.Body.Close()
+}
 
-	if httpResp.StatusCode == http.StatusUnauthorized {
-		c.token = ""
-		// perform at most one retry
-		return c.retry(ctx, req, data)
-	}
+// doHTTP sends the request built by build, retrying per c.retryPolicy until it succeeds,
+// is exhausted, or ctx is done. A 401 response is handled directly: if the client has a
+// TokenProvider, the token is cleared and refreshed before the next attempt, and whether
+// that 401 itself is retried (and how long to wait) is still decided by c.retryPolicy, so
+// it remains bounded like any other status. Without a TokenProvider, a 401 is never
+// retryable — there's no way to obtain different credentials for the next attempt — so
+// doHTTP fails immediately without consulting the policy.
+func (c *Client) doHTTP(ctx context.Context, req *Request, build func() (*http.Request, error), data interface{}) error {
+	for attempt := 0; ; attempt++ {
+		httpReq, err := build()
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
-	}
+		httpResp, doErr := c.http.Do(httpReq)
+		if doErr != nil {
+			retry, wait := c.retryPolicy.ShouldRetry(attempt, req, nil, doErr)
+			if !retry {
+				return fmt.Errorf("failed to do request: %w", doErr)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
 
-	resp := &Response{
-		Data: data,
-	}
+		if httpResp.StatusCode == http.StatusOK {
+			defer httpResp.Body.Close()
 
-	if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
+			resp := &Response{Data: data}
+			if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			if len(resp.Errors) > 0 {
+				return Errors(resp.Errors)
+			}
+			return nil
+		}
 
-	if len(resp.Errors) > 0 {
-		return &resp.Errors[0]
-	}
+		unauthorized := httpResp.StatusCode == http.StatusUnauthorized && c.tokenProvider != nil
 
-	return nil
-}
+		// A 401 is only retryable when there's a TokenProvider to refresh the token
+		// for the next attempt; without one, retrying would just repeat the same
+		// rejected credentials, so fail immediately rather than consulting the
+		// policy (which, for ExponentialBackoffRetryPolicy, treats 401 as retryable
+		// on the assumption that a refreshed token is coming).
+		if httpResp.StatusCode == http.StatusUnauthorized && !unauthorized {
+			httpResp.Body.Close()
+			return fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+		}
 
-// retry retries a GraphQL request.
-func (c *Client) retry(ctx context.Context, req *Request, data interface{}) error {
-	c.mu.Lock()
-	c.retryCount++
-	c.mu.Unlock()
+		retry, wait := c.retryPolicy.ShouldRetry(attempt, req, httpResp, nil)
+		httpResp.Body.Close()
 
-	if c.retryCount > DefaultRetryCount {
-		c.mu.Lock()
-		c.retryCount = 0
-		c.mu.Unlock()
-		return fmt.Errorf("failed to retry, max retry count reached")
-	}
+		if !retry {
+			return fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+		}
+
+		if unauthorized {
+			staleAuth := "Bearer " + c.token
+			c.token = ""
+			if err := c.refreshToken(); err != nil {
+				return fmt.Errorf("failed to refresh token: %w", err)
+			}
+			// runIntercepted copied the rejected token into req.Headers, which
+			// setCommonHeaders applies last; swap in the refreshed token unless an
+			// interceptor set its own Authorization value.
+			if req.Headers["Authorization"] == staleAuth {
+				req.Headers["Authorization"] = "Bearer " + c.token
+			}
+		}
 
-	return c.do(ctx, req, data)
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
 }
 
 func (c *Client) refreshToken() error {
-	var err error
-
 	if c.token == "" && c.tokenProvider != nil {
-		c.token, err = c.tokenProvider.Token()
-		fmt.Println("token", c.token, "err", err, "retryCount", c.retryCount)
+		token, err := c.tokenProvider.Token()
 		if err != nil {
 			return fmt.Errorf("failed to get token: %w", err)
 		}
+		c.token = token
 	}
 
 	return nil
@@ -169,7 +169,7 @@ func TestQuery(t *testing.T) {
 			},
 		},
 		{
-			name: "maximum token refresh attempts reached",
+			name: "token refresh failure",
 			request: request{
 				query: `
 					query Country($code: ID!) {
@@ -197,7 +197,7 @@ func TestQuery(t *testing.T) {
 					return "", errors.New("error")
 				},
 			},
-			expectedErr: errors.New("failed to retry, max retry count reached"),
+			expectedErr: errors.New("failed to refresh token: failed to get token: error"),
 		},
 	}
 
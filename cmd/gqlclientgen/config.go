@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config customizes code generation; loaded from a JSON file via -config.
+type Config struct {
+	// PackageName is the generated file's package. Defaults to "gqlgen".
+	PackageName string `json:"packageName"`
+
+	// Scalars maps a custom GraphQL scalar name to the Go type it should generate,
+	// e.g. {"DateTime": "time.Time", "JSON": "map[string]interface{}"}. Go types that
+	// need an import (like "time.Time") must have that import added manually, or via
+	// ExtraImports.
+	Scalars map[string]string `json:"scalars"`
+
+	// ExtraImports lists additional import paths the generated file needs, typically
+	// alongside a Scalars mapping to a type from that package.
+	ExtraImports []string `json:"extraImports"`
+}
+
+// LoadConfig reads a JSON config file. An empty path is not an error; it returns a Config
+// holding only the built-in defaults. A path to a file that cannot be read is an error.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{PackageName: "gqlgen"}
+	if path == "" {
+		return cfg, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %q: %w", path, err)
+	}
+
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %q: %w", path, err)
+	}
+	if cfg.PackageName == "" {
+		cfg.PackageName = "gqlgen"
+	}
+
+	return cfg, nil
+}
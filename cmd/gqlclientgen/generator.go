@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stefanprifti/gqlclient/schema"
+)
+
+// builtinScalars maps the spec-defined scalars to their Go equivalents. Custom scalars are
+// resolved through Config.Scalars instead, falling back to interface{} if unmapped.
+var builtinScalars = map[string]string{
+	"ID":      "string",
+	"String":  "string",
+	"Int":     "int",
+	"Float":   "float64",
+	"Boolean": "bool",
+}
+
+// generator accumulates the Go type declarations a set of operations need, deduplicating
+// schema-wide types (enums, input objects) that multiple operations reference.
+type generator struct {
+	schema *schema.Schema
+	config *Config
+
+	structs    []string
+	structSeen map[string]bool
+	enums      []string
+	enumSeen   map[string]bool
+}
+
+func newGenerator(sch *schema.Schema, cfg *Config) *generator {
+	return &generator{
+		schema:     sch,
+		config:     cfg,
+		structSeen: map[string]bool{},
+		enumSeen:   map[string]bool{},
+	}
+}
+
+// generatedOperation is the Go source generated for a single operation.
+type generatedOperation struct {
+	VariablesType string
+	ResponseType  string
+	FuncSource    string
+}
+
+// GenerateOperation generates the variables struct, response struct, and (for query and
+// mutation operations) a typed wrapper function, taking a *gqlclient.Client, for op.
+func (g *generator) GenerateOperation(op *Operation, doc *Document) (*generatedOperation, error) {
+	varsType := exportedName(op.Name) + "Variables"
+	if err := g.buildVariablesStruct(op, varsType); err != nil {
+		return nil, err
+	}
+
+	respType := exportedName(op.Name) + "Response"
+	root, err := g.schema.RootType(op.Type)
+	if err != nil {
+		return nil, fmt.Errorf("operation %q: %w", op.Name, err)
+	}
+	if err := g.buildObjectStruct(respType, root, op.ResolvedSelection); err != nil {
+		return nil, fmt.Errorf("operation %q: %w", op.Name, err)
+	}
+
+	queryText, err := printOperation(op, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &generatedOperation{VariablesType: varsType, ResponseType: respType}
+
+	if op.Type == "subscription" {
+		// Subscribe's callback-based API doesn't fit the single typed call/response
+		// shape the other operation kinds get; callers use gqlclient.Client.Subscribe
+		// directly with the generated types above.
+		return result, nil
+	}
+
+	method := "Query"
+	if op.Type == "mutation" {
+		method = "Mutation"
+	}
+
+	queryConst := unexportedName(op.Name) + "Query"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "const %s = `%s`\n\n", queryConst, queryText)
+	fmt.Fprintf(&b, "// %s executes the %s %q operation.\n", exportedName(op.Name), op.Type, op.Name)
+	fmt.Fprintf(&b, "func %s(ctx context.Context, c *gqlclient.Client, variables %s) (*%s, error) {\n", exportedName(op.Name), varsType, respType)
+	fmt.Fprintf(&b, "\tvar resp %s\n", respType)
+	fmt.Fprintf(&b, "\tif err := c.%s(ctx, %s, variables, &resp); err != nil {\n", method, queryConst)
+	b.WriteString("\t\treturn nil, err\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn &resp, nil\n")
+	b.WriteString("}\n")
+
+	result.FuncSource = b.String()
+	return result, nil
+}
+
+// buildVariablesStruct emits the <Op>Variables struct for op's variable definitions.
+func (g *generator) buildVariablesStruct(op *Operation, structName string) error {
+	var fields []structField
+	for _, v := range op.Variables {
+		goType, err := g.goType(v.Type, nil, exportedName(op.Name)+exportedName(v.Name))
+		if err != nil {
+			return fmt.Errorf("variable $%s: %w", v.Name, err)
+		}
+		fields = append(fields, structField{GoName: exportedName(v.Name), GoType: goType, JSONName: v.Name})
+	}
+
+	g.emitStruct(structName, fields)
+	return nil
+}
+
+type structField struct {
+	GoName   string
+	GoType   string
+	JSONName string
+}
+
+// emitStruct records a struct declaration, skipping ones already emitted under this name.
+func (g *generator) emitStruct(name string, fields []structField) {
+	if g.structSeen[name] {
+		return
+	}
+	g.structSeen[name] = true
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", f.GoName, f.GoType, f.JSONName)
+	}
+	b.WriteString("}\n")
+
+	g.structs = append(g.structs, b.String())
+}
+
+// buildObjectStruct emits the Go struct for an object/interface type, scoped to the given
+// selection set, under the given Go type name.
+func (g *generator) buildObjectStruct(structName string, t *schema.NamedType, sel []*SelectionField) error {
+	var fields []structField
+	for _, s := range sel {
+		if s.Name == "__typename" {
+			fields = append(fields, structField{GoName: "Typename", GoType: "string", JSONName: "__typename"})
+			continue
+		}
+
+		field := t.FieldByName(s.Name)
+		if field == nil {
+			return fmt.Errorf("type %q has no field %q", t.Name, s.Name)
+		}
+
+		goType, err := g.goType(field.Type, s.Selection, structName+exportedName(s.Alias))
+		if err != nil {
+			return fmt.Errorf("field %q: %w", s.Name, err)
+		}
+
+		fields = append(fields, structField{GoName: exportedName(s.Alias), GoType: goType, JSONName: s.Alias})
+	}
+
+	g.emitStruct(structName, fields)
+	return nil
+}
+
+// goType resolves ref to a Go type string, generating any nested struct/enum declarations
+// sel requires along the way. namePrefix names a struct generated for an object type found
+// at this position; it is unused for scalars, enums, and shared input objects.
+func (g *generator) goType(ref *schema.TypeRef, sel []*SelectionField, namePrefix string) (string, error) {
+	return g.goTypeRec(ref, sel, namePrefix, true)
+}
+
+func (g *generator) goTypeRec(ref *schema.TypeRef, sel []*SelectionField, namePrefix string, nullable bool) (string, error) {
+	switch ref.Kind {
+	case "NON_NULL":
+		return g.goTypeRec(ref.OfType, sel, namePrefix, false)
+	case "LIST":
+		elem, err := g.goTypeRec(ref.OfType, sel, namePrefix, true)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+
+	base, err := g.namedGoType(ref.Name, sel, namePrefix)
+	if err != nil {
+		return "", err
+	}
+	if nullable && base != "interface{}" && !strings.HasPrefix(base, "[]") {
+		return "*" + base, nil
+	}
+	return base, nil
+}
+
+// namedGoType resolves a leaf (unwrapped) named type to a Go type, emitting a struct or enum
+// declaration as needed.
+func (g *generator) namedGoType(name string, sel []*SelectionField, namePrefix string) (string, error) {
+	if goType, ok := builtinScalars[name]; ok {
+		return goType, nil
+	}
+
+	t, ok := g.schema.Types[name]
+	if !ok {
+		if goType, ok := g.config.Scalars[name]; ok {
+			return goType, nil
+		}
+		return "interface{}", nil
+	}
+
+	switch t.Kind {
+	case "SCALAR":
+		if goType, ok := g.config.Scalars[name]; ok {
+			return goType, nil
+		}
+		return "interface{}", nil
+
+	case "ENUM":
+		g.emitEnum(t)
+		return t.Name, nil
+
+	case "OBJECT", "INTERFACE":
+		if err := g.buildObjectStruct(namePrefix, t, sel); err != nil {
+			return "", err
+		}
+		return namePrefix, nil
+
+	case "INPUT_OBJECT":
+		if !g.structSeen[t.Name] {
+			var fields []structField
+			for _, f := range t.InputFields {
+				goType, err := g.goType(f.Type, nil, t.Name+exportedName(f.Name))
+				if err != nil {
+					return "", fmt.Errorf("input field %s.%s: %w", t.Name, f.Name, err)
+				}
+				fields = append(fields, structField{GoName: exportedName(f.Name), GoType: goType, JSONName: f.Name})
+			}
+			g.emitStruct(t.Name, fields)
+		}
+		return t.Name, nil
+
+	default:
+		// UNION and other kinds without inline-fragment support in this generator.
+		return "interface{}", nil
+	}
+}
+
+func (g *generator) emitEnum(t *schema.NamedType) {
+	if g.enumSeen[t.Name] {
+		return
+	}
+	g.enumSeen[t.Name] = true
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s string\n\n", t.Name)
+
+	values := make([]string, len(t.EnumValues))
+	for i, v := range t.EnumValues {
+		values[i] = v.Name
+	}
+	sort.Strings(values)
+
+	if len(values) > 0 {
+		b.WriteString("const (\n")
+		for _, v := range values {
+			fmt.Fprintf(&b, "\t%s%s %s = %q\n", t.Name, exportedName(v), t.Name, v)
+		}
+		b.WriteString(")\n")
+	}
+
+	g.enums = append(g.enums, b.String())
+}
+
+// exportedName converts a GraphQL identifier (camelCase or snake_case) into an exported Go
+// identifier.
+func exportedName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// unexportedName converts a GraphQL identifier into an unexported Go identifier.
+func unexportedName(name string) string {
+	exported := exportedName(name)
+	if exported == "" {
+		return exported
+	}
+	return strings.ToLower(exported[:1]) + exported[1:]
+}
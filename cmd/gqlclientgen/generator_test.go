@@ -0,0 +1,238 @@
+package main
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stefanprifti/gqlclient/schema"
+)
+
+const testSDL = `
+schema {
+	query: Query
+	subscription: Subscription
+}
+
+enum Status {
+	ACTIVE
+	INACTIVE
+}
+
+type Country {
+	code: String!
+	name: String!
+	status: Status
+}
+
+input CountryFilter {
+	code: String
+}
+
+type Query {
+	country(filter: CountryFilter): Country
+	countries: [Country!]!
+}
+
+type Subscription {
+	countryUpdated: Country!
+}
+`
+
+const testOperation = `
+query GetCountry($filter: CountryFilter) {
+	country(filter: $filter) {
+		code
+		name
+		status
+	}
+}
+`
+
+func TestGenerateOperationEndToEnd(t *testing.T) {
+	sch, err := schema.ParseSDL(strings.NewReader(testSDL))
+	if err != nil {
+		t.Fatalf("ParseSDL: %v", err)
+	}
+
+	doc, err := ParseOperations(map[string]string{"get_country.graphql": testOperation})
+	if err != nil {
+		t.Fatalf("ParseOperations: %v", err)
+	}
+	if len(doc.Operations) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(doc.Operations))
+	}
+
+	gen := newGenerator(sch, &Config{PackageName: "gqlgen"})
+	generated, err := gen.GenerateOperation(doc.Operations[0], doc)
+	if err != nil {
+		t.Fatalf("GenerateOperation: %v", err)
+	}
+
+	if generated.VariablesType != "GetCountryVariables" {
+		t.Errorf("VariablesType = %q, want GetCountryVariables", generated.VariablesType)
+	}
+	if generated.ResponseType != "GetCountryResponse" {
+		t.Errorf("ResponseType = %q, want GetCountryResponse", generated.ResponseType)
+	}
+	if generated.FuncSource == "" {
+		t.Fatal("expected a wrapper func for a query operation")
+	}
+
+	src := renderFile(&Config{PackageName: "gqlgen"}, gen, []string{generated.FuncSource})
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("generated source is not even gofmt-parseable:\n%s\n\nerror: %v", src, err)
+	}
+
+	if !strings.Contains(src, "type Status string") {
+		t.Errorf("expected generated source to declare the Status enum, got:\n%s", src)
+	}
+	if !strings.Contains(src, "type CountryFilter struct") {
+		t.Errorf("expected generated source to declare the CountryFilter input struct, got:\n%s", src)
+	}
+	if !strings.Contains(src, "func GetCountry(ctx context.Context, c *gqlclient.Client, variables GetCountryVariables)") {
+		t.Errorf("expected a free function taking *gqlclient.Client, got:\n%s", src)
+	}
+	if !strings.Contains(src, "country(filter: $filter)") {
+		t.Errorf("expected the printed query to keep the field's arguments, got:\n%s", src)
+	}
+
+	buildGeneratedPackage(t, src)
+}
+
+// buildGeneratedPackage writes src into its own module (replacing gqlclient with this
+// checkout) and runs `go build`/`go vet` on it, so a regression like an illegal method
+// receiver on an imported type is caught as an actual compile failure rather than just
+// gofmt-parseable syntax.
+func buildGeneratedPackage(t *testing.T, src string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	repoRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("failed to resolve repo root: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gen.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("failed to write generated source: %v", err)
+	}
+
+	goMod := "module gqlclientgen_e2e_test\n\ngo 1.21\n\nrequire github.com/stefanprifti/gqlclient v0.0.0\n\nreplace github.com/stefanprifti/gqlclient => " + repoRoot + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	runGo := func(args ...string) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOSUMDB=off")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	runGo("mod", "tidy")
+	runGo("build", "./...")
+	runGo("vet", "./...")
+}
+
+// TestGenerateSubscriptionOnlyFileCompiles covers the documented no-wrapper-func case
+// (GenerateOperation skips the wrapper for subscriptions): renderFile must not emit the
+// "context"/gqlclient imports when nothing in the file references them, or the generated
+// file fails to compile with "imported and not used".
+func TestGenerateSubscriptionOnlyFileCompiles(t *testing.T) {
+	sch, err := schema.ParseSDL(strings.NewReader(testSDL))
+	if err != nil {
+		t.Fatalf("ParseSDL: %v", err)
+	}
+
+	doc, err := ParseOperations(map[string]string{"on_country_updated.graphql": `
+		subscription OnCountryUpdated {
+			countryUpdated {
+				code
+			}
+		}
+	`})
+	if err != nil {
+		t.Fatalf("ParseOperations: %v", err)
+	}
+
+	gen := newGenerator(sch, &Config{PackageName: "gqlgen"})
+	generated, err := gen.GenerateOperation(doc.Operations[0], doc)
+	if err != nil {
+		t.Fatalf("GenerateOperation: %v", err)
+	}
+	if generated.FuncSource != "" {
+		t.Fatalf("expected no wrapper func for a subscription operation, got:\n%s", generated.FuncSource)
+	}
+
+	src := renderFile(&Config{PackageName: "gqlgen"}, gen, nil)
+	if strings.Contains(src, `"context"`) || strings.Contains(src, `"github.com/stefanprifti/gqlclient"`) {
+		t.Errorf("expected no unused context/gqlclient imports in a subscription-only file, got:\n%s", src)
+	}
+
+	buildGeneratedPackage(t, src)
+}
+
+func TestPrintOperationIncludesTransitiveFragments(t *testing.T) {
+	sources := map[string]string{
+		"op.graphql": `
+			query GetCountry {
+				country {
+					...CountryFields
+				}
+			}
+
+			fragment CountryFields on Country {
+				code
+				...CountryExtra
+			}
+
+			fragment CountryExtra on Country {
+				name
+			}
+		`,
+	}
+
+	doc, err := ParseOperations(sources)
+	if err != nil {
+		t.Fatalf("ParseOperations: %v", err)
+	}
+
+	queryText, err := printOperation(doc.Operations[0], doc)
+	if err != nil {
+		t.Fatalf("printOperation: %v", err)
+	}
+
+	if !strings.Contains(queryText, "fragment CountryFields on Country") {
+		t.Errorf("expected the CountryFields fragment definition, got:\n%s", queryText)
+	}
+	if !strings.Contains(queryText, "fragment CountryExtra on Country") {
+		t.Errorf("expected the transitively-spread CountryExtra fragment definition, got:\n%s", queryText)
+	}
+}
+
+func TestGenerateOperationUnknownField(t *testing.T) {
+	sch, err := schema.ParseSDL(strings.NewReader(testSDL))
+	if err != nil {
+		t.Fatalf("ParseSDL: %v", err)
+	}
+
+	doc, err := ParseOperations(map[string]string{"bad.graphql": "query Bad { country { missing } }"})
+	if err != nil {
+		t.Fatalf("ParseOperations: %v", err)
+	}
+
+	gen := newGenerator(sch, &Config{PackageName: "gqlgen"})
+	if _, err := gen.GenerateOperation(doc.Operations[0], doc); err == nil {
+		t.Fatal("expected an error for a selection on an undefined field")
+	}
+}
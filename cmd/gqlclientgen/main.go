@@ -0,0 +1,214 @@
+// Command gqlclientgen generates typed Go wrappers for GraphQL operations from a schema and
+// a directory of .graphql operation files.
+//
+// Usage:
+//
+//	gqlclientgen -schema schema.json -ops ./graphql -out ./gqlgen/operations.go
+//
+// -schema accepts either introspection JSON (as produced by schema.Introspect, see the
+// `gqlclientgen introspect` subcommand below) or a .graphql/.graphqls SDL file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stefanprifti/gqlclient/schema"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "introspect" {
+		runIntrospect(os.Args[2:])
+		return
+	}
+
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "gqlclientgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("gqlclientgen", flag.ExitOnError)
+	schemaPath := fs.String("schema", "", "path to a schema file (introspection JSON or SDL)")
+	opsDir := fs.String("ops", "", "directory of .graphql operation files")
+	outPath := fs.String("out", "", "output Go file path")
+	configPath := fs.String("config", "", "optional JSON config file (package name, scalar mappings)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *schemaPath == "" || *opsDir == "" || *outPath == "" {
+		return fmt.Errorf("-schema, -ops, and -out are required")
+	}
+
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	sch, err := loadSchema(*schemaPath)
+	if err != nil {
+		return err
+	}
+
+	sources, err := loadOperationSources(*opsDir)
+	if err != nil {
+		return err
+	}
+
+	doc, err := ParseOperations(sources)
+	if err != nil {
+		return fmt.Errorf("failed to parse operations: %w", err)
+	}
+
+	sort.Slice(doc.Operations, func(i, j int) bool {
+		return doc.Operations[i].Name < doc.Operations[j].Name
+	})
+
+	gen := newGenerator(sch, cfg)
+
+	var funcs []string
+	for _, op := range doc.Operations {
+		generated, err := gen.GenerateOperation(op, doc)
+		if err != nil {
+			return fmt.Errorf("failed to generate %q: %w", op.Name, err)
+		}
+		if generated.FuncSource != "" {
+			funcs = append(funcs, generated.FuncSource)
+		}
+	}
+
+	src := renderFile(cfg, gen, funcs)
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("failed to format generated source: %w\n%s", err, src)
+	}
+
+	if err := os.WriteFile(*outPath, formatted, 0o644); err != nil {
+		return fmt.Errorf("failed to write %q: %w", *outPath, err)
+	}
+
+	return nil
+}
+
+func loadSchema(path string) (*schema.Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema %q: %w", path, err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(path, ".json") {
+		return schema.ParseIntrospectionJSON(f)
+	}
+	return schema.ParseSDL(f)
+}
+
+func loadOperationSources(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operations directory %q: %w", dir, err)
+	}
+
+	sources := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".graphql") {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		sources[e.Name()] = string(b)
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no .graphql files found in %q", dir)
+	}
+
+	return sources, nil
+}
+
+func renderFile(cfg *Config, gen *generator, funcs []string) string {
+	var b strings.Builder
+
+	b.WriteString("// Code generated by gqlclientgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", cfg.PackageName)
+
+	// "context" and gqlclient are only referenced by the query/mutation wrapper funcs;
+	// an operation set containing only subscriptions (which get types but no wrapper,
+	// see GenerateOperation) would otherwise produce an "imported and not used" file.
+	needsWrapperImports := len(funcs) > 0
+	if needsWrapperImports || len(cfg.ExtraImports) > 0 {
+		b.WriteString("import (\n")
+		if needsWrapperImports {
+			b.WriteString("\t\"context\"\n\n")
+			b.WriteString("\t\"github.com/stefanprifti/gqlclient\"\n")
+		}
+		for _, imp := range cfg.ExtraImports {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	for _, e := range gen.enums {
+		b.WriteString(e)
+		b.WriteString("\n")
+	}
+	for _, s := range gen.structs {
+		b.WriteString(s)
+		b.WriteString("\n")
+	}
+	for _, f := range funcs {
+		b.WriteString(f)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// runIntrospect runs `gqlclientgen introspect -endpoint <url> -out <schema.json>`, a small
+// helper to bootstrap a schema file from a live server.
+func runIntrospect(args []string) {
+	fs := flag.NewFlagSet("gqlclientgen introspect", flag.ExitOnError)
+	endpoint := fs.String("endpoint", "", "GraphQL endpoint to introspect")
+	outPath := fs.String("out", "schema.json", "where to write the introspection result")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, "gqlclientgen:", err)
+		os.Exit(1)
+	}
+
+	if *endpoint == "" {
+		fmt.Fprintln(os.Stderr, "gqlclientgen: -endpoint is required")
+		os.Exit(1)
+	}
+
+	if err := introspectToFile(*endpoint, *outPath); err != nil {
+		fmt.Fprintln(os.Stderr, "gqlclientgen:", err)
+		os.Exit(1)
+	}
+}
+
+func introspectToFile(endpoint, outPath string) error {
+	sch, err := schema.Introspect(context.Background(), endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to introspect %q: %w", endpoint, err)
+	}
+
+	b, err := sch.MarshalIntrospectionJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return os.WriteFile(outPath, b, 0o644)
+}
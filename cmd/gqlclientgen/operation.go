@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/stefanprifti/gqlclient/schema"
+)
+
+// Operation is a single named query/mutation/subscription parsed from a .graphql file.
+type Operation struct {
+	Type      string // "query", "mutation", or "subscription"
+	Name      string
+	Variables []VariableDef
+	Source    string
+
+	// Selection is the selection set as written, with fragment spreads left unresolved
+	// (Name == "" and Alias holding the fragment name) so the operation can be printed
+	// back out with its fragments intact.
+	Selection []*SelectionField
+
+	// ResolvedSelection has every fragment spread inlined, for Go type generation.
+	ResolvedSelection []*SelectionField
+}
+
+// VariableDef is one `$name: Type` entry in an operation's variable list.
+type VariableDef struct {
+	Name string
+	Type *schema.TypeRef
+}
+
+// Fragment is a `fragment Name on Type { ... }` definition.
+type Fragment struct {
+	Name      string
+	On        string
+	Selection []*SelectionField
+}
+
+// SelectionField is one field in a selection set. A fragment spread ("...Name") is kept as a
+// placeholder SelectionField with an empty Name and Alias holding the fragment name;
+// ParseOperations inlines the referenced Fragment's selection into
+// Operation.ResolvedSelection, so downstream code generation only ever sees plain fields. An
+// inline fragment ("... on Type { ... }") is merged flatly into the enclosing selection set
+// at parse time, and its type condition is dropped.
+type SelectionField struct {
+	Name      string
+	Alias     string
+	Arguments string // printed argument list, e.g. "code: $code", or "" if the field takes none
+	Selection []*SelectionField
+}
+
+// Document is every operation and fragment definition found in a set of .graphql files.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// ParseOperations parses the .graphql source files in sources (filename -> contents) into a
+// Document, resolving fragment spreads against fragments defined anywhere in the set.
+func ParseOperations(sources map[string]string) (*Document, error) {
+	doc := &Document{Fragments: map[string]*Fragment{}}
+
+	var rawOps []*Operation
+	for name, src := range sources {
+		p := &opParser{tokens: tokenizeOperation(src)}
+		for !p.atEnd() {
+			switch p.peek() {
+			case "fragment":
+				f, err := p.parseFragment()
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				doc.Fragments[f.Name] = f
+			case "query", "mutation", "subscription":
+				op, err := p.parseOperation()
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				op.Source = name
+				rawOps = append(rawOps, op)
+			default:
+				return nil, fmt.Errorf("%s: unexpected token %q", name, p.peek())
+			}
+		}
+	}
+
+	for _, op := range rawOps {
+		resolved, err := resolveFragments(op.Selection, doc.Fragments, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s (%s): %w", op.Name, op.Source, err)
+		}
+		op.ResolvedSelection = resolved
+		doc.Operations = append(doc.Operations, op)
+	}
+
+	return doc, nil
+}
+
+// resolveFragments replaces fragment spreads with the referenced fragment's fields,
+// recursively, detecting cycles via active.
+func resolveFragments(sel []*SelectionField, fragments map[string]*Fragment, active map[string]bool) ([]*SelectionField, error) {
+	resolved := make([]*SelectionField, 0, len(sel))
+
+	for _, f := range sel {
+		if f.Name == "" {
+			// Fragment spread placeholder: Alias carries the fragment name.
+			name := f.Alias
+			if active[name] {
+				return nil, fmt.Errorf("fragment %q spreads itself", name)
+			}
+			frag, ok := fragments[name]
+			if !ok {
+				return nil, fmt.Errorf("undefined fragment %q", name)
+			}
+
+			nextActive := map[string]bool{name: true}
+			for k := range active {
+				nextActive[k] = true
+			}
+
+			fields, err := resolveFragments(frag.Selection, fragments, nextActive)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, fields...)
+			continue
+		}
+
+		children, err := resolveFragments(f.Selection, fragments, active)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, &SelectionField{Name: f.Name, Alias: f.Alias, Arguments: f.Arguments, Selection: children})
+	}
+
+	return resolved, nil
+}
+
+// tokenizeOperation splits GraphQL operation-language source into tokens, dropping comments.
+// A string literal is kept as a single token, quotes included.
+func tokenizeOperation(src string) []string {
+	var tokens []string
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, string(runes[start:min(i+1, len(runes))]))
+		case r == '.' && i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.':
+			tokens = append(tokens, "...")
+			i += 2
+		case strings.ContainsRune("{}()[]:!=$@,", r):
+			tokens = append(tokens, string(r))
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("{}()[]:!=$@,#\"", runes[i]) &&
+				!(runes[i] == '.' && i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+			i--
+		}
+	}
+
+	return tokens
+}
+
+type opParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *opParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *opParser) peek() string {
+	if p.atEnd() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *opParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *opParser) skipBalanced(open, close string) {
+	depth := 1
+	for depth > 0 && !p.atEnd() {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+		}
+	}
+}
+
+func (p *opParser) skipDirectives() {
+	for p.peek() == "@" {
+		p.next()
+		p.next()
+		if p.peek() == "(" {
+			p.next()
+			p.skipBalanced("(", ")")
+		}
+	}
+}
+
+func (p *opParser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.next()}
+
+	if p.peek() != "(" && p.peek() != "{" && p.peek() != "@" {
+		op.Name = p.next()
+	}
+
+	if p.peek() == "(" {
+		p.next()
+		for p.peek() != ")" && !p.atEnd() {
+			if p.next() != "$" {
+				return nil, fmt.Errorf("expected '$' in variable definition")
+			}
+			varName := p.next()
+			if p.next() != ":" {
+				return nil, fmt.Errorf("expected ':' after $%s", varName)
+			}
+			typeRef, err := p.parseTypeRef()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() == "=" {
+				p.next()
+				p.next()
+			}
+			p.skipDirectives()
+			op.Variables = append(op.Variables, VariableDef{Name: varName, Type: typeRef})
+		}
+		p.next() // ")"
+	}
+
+	p.skipDirectives()
+
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selection = sel
+
+	return op, nil
+}
+
+func (p *opParser) parseFragment() (*Fragment, error) {
+	p.next() // "fragment"
+	name := p.next()
+	if p.next() != "on" {
+		return nil, fmt.Errorf("expected 'on' in fragment %q", name)
+	}
+	onType := p.next()
+	p.skipDirectives()
+
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fragment{Name: name, On: onType, Selection: sel}, nil
+}
+
+func (p *opParser) parseSelectionSet() ([]*SelectionField, error) {
+	if p.next() != "{" {
+		return nil, fmt.Errorf("expected '{' to start selection set")
+	}
+
+	var fields []*SelectionField
+	for p.peek() != "}" && !p.atEnd() {
+		if p.peek() == "..." {
+			p.next()
+			if p.peek() == "on" {
+				p.next()
+				p.next() // type condition, merged flatly (see SelectionField doc)
+				p.skipDirectives()
+				sel, err := p.parseSelectionSet()
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, sel...)
+				continue
+			}
+
+			fragName := p.next()
+			p.skipDirectives()
+			fields = append(fields, &SelectionField{Alias: fragName})
+			continue
+		}
+
+		first := p.next()
+		name, alias := first, first
+		if p.peek() == ":" {
+			p.next()
+			name = p.next()
+		}
+
+		var args string
+		if p.peek() == "(" {
+			p.next()
+			var err error
+			args, err = p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+		}
+		p.skipDirectives()
+
+		field := &SelectionField{Name: name, Alias: alias, Arguments: args}
+		if p.peek() == "{" {
+			sel, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			field.Selection = sel
+		}
+		fields = append(fields, field)
+	}
+	p.next() // "}"
+
+	return fields, nil
+}
+
+// parseArguments parses a field's argument list, assuming the opening "(" has already been
+// consumed, and returns it re-printed as "name: value, ...". Variable references ($code) are
+// the only values code generation needs to round-trip correctly; other literals are passed
+// through as their token text.
+func (p *opParser) parseArguments() (string, error) {
+	var args []string
+	for p.peek() != ")" && !p.atEnd() {
+		name := p.next()
+		if p.next() != ":" {
+			return "", fmt.Errorf("expected ':' after argument %q", name)
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, name+": "+value)
+		if p.peek() == "," {
+			p.next()
+		}
+	}
+	p.next() // ")"
+	return strings.Join(args, ", "), nil
+}
+
+// parseValue parses and re-prints a single GraphQL argument value: a variable reference, a
+// list, an input object, or a scalar/enum literal token.
+func (p *opParser) parseValue() (string, error) {
+	switch p.peek() {
+	case "$":
+		p.next()
+		return "$" + p.next(), nil
+	case "[":
+		p.next()
+		var items []string
+		for p.peek() != "]" && !p.atEnd() {
+			item, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+			if p.peek() == "," {
+				p.next()
+			}
+		}
+		p.next() // "]"
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case "{":
+		p.next()
+		var fields []string
+		for p.peek() != "}" && !p.atEnd() {
+			name := p.next()
+			if p.next() != ":" {
+				return "", fmt.Errorf("expected ':' in object value for field %q", name)
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, name+": "+value)
+			if p.peek() == "," {
+				p.next()
+			}
+		}
+		p.next() // "}"
+		return "{" + strings.Join(fields, ", ") + "}", nil
+	default:
+		return p.next(), nil
+	}
+}
+
+func (p *opParser) parseTypeRef() (*schema.TypeRef, error) {
+	if p.peek() == "[" {
+		p.next()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != "]" {
+			return nil, fmt.Errorf("expected ']' to close list type")
+		}
+
+		ref := &schema.TypeRef{Kind: "LIST", OfType: inner}
+		if p.peek() == "!" {
+			p.next()
+			ref = &schema.TypeRef{Kind: "NON_NULL", OfType: ref}
+		}
+		return ref, nil
+	}
+
+	name := p.next()
+	ref := &schema.TypeRef{Kind: "NAMED", Name: name}
+	if p.peek() == "!" {
+		p.next()
+		ref = &schema.TypeRef{Kind: "NON_NULL", OfType: ref}
+	}
+	return ref, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stefanprifti/gqlclient/schema"
+)
+
+// printOperation renders op back into GraphQL source text, appending the definitions of
+// every fragment it references (transitively), so the wire query keeps using fragments
+// instead of the inlined form generated types are built from.
+func printOperation(op *Operation, doc *Document) (string, error) {
+	var b strings.Builder
+
+	b.WriteString(op.Type)
+	if op.Name != "" {
+		b.WriteString(" ")
+		b.WriteString(op.Name)
+	}
+	if len(op.Variables) > 0 {
+		b.WriteString("(")
+		for i, v := range op.Variables {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%s: %s", v.Name, printTypeRefGraphQL(v.Type))
+		}
+		b.WriteString(")")
+	}
+	b.WriteString(" ")
+
+	printSelectionSet(&b, op.Selection, 0)
+
+	used := map[string]bool{}
+	collectFragmentRefs(op.Selection, doc, used)
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		frag, ok := doc.Fragments[name]
+		if !ok {
+			return "", fmt.Errorf("operation %q references undefined fragment %q", op.Name, name)
+		}
+		b.WriteString("\n\nfragment ")
+		b.WriteString(frag.Name)
+		b.WriteString(" on ")
+		b.WriteString(frag.On)
+		b.WriteString(" ")
+		printSelectionSet(&b, frag.Selection, 0)
+	}
+
+	return b.String(), nil
+}
+
+// collectFragmentRefs records every fragment sel spreads, transitively: a fragment that
+// itself spreads another fragment pulls that one in too, so the printed document defines
+// every fragment it (recursively) references.
+func collectFragmentRefs(sel []*SelectionField, doc *Document, used map[string]bool) {
+	for _, f := range sel {
+		if f.Name == "" {
+			name := f.Alias
+			if used[name] {
+				continue
+			}
+			used[name] = true
+			if frag, ok := doc.Fragments[name]; ok {
+				collectFragmentRefs(frag.Selection, doc, used)
+			}
+			continue
+		}
+		collectFragmentRefs(f.Selection, doc, used)
+	}
+}
+
+func printSelectionSet(b *strings.Builder, sel []*SelectionField, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	b.WriteString("{\n")
+	for _, f := range sel {
+		b.WriteString(indent)
+		if f.Name == "" {
+			fmt.Fprintf(b, "...%s\n", f.Alias)
+			continue
+		}
+		if f.Alias != f.Name {
+			fmt.Fprintf(b, "%s: %s", f.Alias, f.Name)
+		} else {
+			b.WriteString(f.Name)
+		}
+		if f.Arguments != "" {
+			fmt.Fprintf(b, "(%s)", f.Arguments)
+		}
+		if len(f.Selection) > 0 {
+			b.WriteString(" ")
+			printSelectionSet(b, f.Selection, depth+1)
+		} else {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString("}\n")
+}
+
+// printTypeRefGraphQL renders a variable's type reference back into GraphQL syntax, e.g.
+// "[String!]!".
+func printTypeRefGraphQL(ref *schema.TypeRef) string {
+	switch ref.Kind {
+	case "NON_NULL":
+		return printTypeRefGraphQL(ref.OfType) + "!"
+	case "LIST":
+		return "[" + printTypeRefGraphQL(ref.OfType) + "]"
+	default:
+		return ref.Name
+	}
+}
@@ -1,19 +1,57 @@
 package gqlclient
 
-import "encoding/json"
+import (
+	"fmt"
+	"strings"
+)
 
 type ErrorLocation struct {
 	Line, Column int
 }
 
-// Error is a GraphQL error.
+// Error is a single GraphQL error.
 type Error struct {
-	Message    string
-	Locations  []ErrorLocation
-	Path       []interface{}
-	Extensions json.RawMessage
+	Message    string                 `json:"message"`
+	Locations  []ErrorLocation        `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 func (err *Error) Error() string {
 	return "gqlclient: server failure: " + err.Message
 }
+
+// Code returns the error's extensions.code, or "" if the error has none.
+func (err *Error) Code() string {
+	code, _ := err.Extensions["code"].(string)
+	return code
+}
+
+// Errors aggregates every error a GraphQL response returned. It implements error and
+// Unwrap() []error, so callers can use errors.As/errors.Is to pull out individual *Error
+// values instead of only ever seeing the first one.
+type Errors []Error
+
+func (errs Errors) Error() string {
+	switch len(errs) {
+	case 0:
+		return "gqlclient: server failure"
+	case 1:
+		return errs[0].Error()
+	default:
+		messages := make([]string, len(errs))
+		for i := range errs {
+			messages[i] = errs[i].Message
+		}
+		return fmt.Sprintf("gqlclient: %d server failures: %s", len(errs), strings.Join(messages, "; "))
+	}
+}
+
+// Unwrap allows errors.Is/errors.As to range over the individual *Error values.
+func (errs Errors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i := range errs {
+		unwrapped[i] = &errs[i]
+	}
+	return unwrapped
+}
@@ -0,0 +1,69 @@
+package gqlclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+func TestQueryReturnsAllErrorsAndPartialData(t *testing.T) {
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(strings.NewReader(`
+							{
+								"data": {"country": {"code": "AL"}},
+								"errors": [
+									{"message": "first failure", "extensions": {"code": "FIRST"}},
+									{"message": "second failure", "extensions": {"code": "SECOND"}}
+								]
+							}`)),
+					}, nil
+				},
+			},
+		},
+	})
+
+	resp := struct {
+		Country struct {
+			Code string `json:"code"`
+		} `json:"country"`
+	}{}
+
+	err := client.Query(context.Background(), `query { country { code } }`, map[string]interface{}{}, &resp)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	var gqlErrs gqlclient.Errors
+	if !errors.As(err, &gqlErrs) {
+		t.Fatalf("expected error to be a gqlclient.Errors, got %T", err)
+	}
+	if len(gqlErrs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(gqlErrs))
+	}
+	if gqlErrs[0].Code() != "FIRST" || gqlErrs[1].Code() != "SECOND" {
+		t.Errorf("expected codes FIRST and SECOND, got %q and %q", gqlErrs[0].Code(), gqlErrs[1].Code())
+	}
+
+	var single *gqlclient.Error
+	if !errors.As(err, &single) {
+		t.Fatalf("expected errors.As to find an individual *gqlclient.Error")
+	}
+	if single.Message != "first failure" {
+		t.Errorf("expected errors.As to find the first error, got %q", single.Message)
+	}
+
+	if resp.Country.Code != "AL" {
+		t.Errorf("expected partial data to be decoded despite errors, got %q", resp.Country.Code)
+	}
+}
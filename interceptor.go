@@ -0,0 +1,59 @@
+package gqlclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestInterceptor wraps a Query or Mutation call. It receives the request and a next
+// function that invokes the rest of the chain (ultimately the HTTP transport); an
+// interceptor can inspect or modify req before calling next, and inspect the response or
+// error next returns before passing it back up the chain.
+//
+// Interceptors run around the whole request, including retries: next only returns once the
+// client's RetryPolicy has stopped retrying.
+type RequestInterceptor func(ctx context.Context, req *Request, next func(context.Context, *Request) (*Response, error)) (*Response, error)
+
+// chainInterceptors composes interceptors around terminal, in the order given: the first
+// interceptor is outermost and runs first.
+func chainInterceptors(interceptors []RequestInterceptor, terminal func(context.Context, *Request) (*Response, error)) func(context.Context, *Request) (*Response, error) {
+	next := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		inner := next
+		next = func(ctx context.Context, req *Request) (*Response, error) {
+			return interceptor(ctx, req, inner)
+		}
+	}
+	return next
+}
+
+// TransportInterceptor decorates an http.RoundTrip call. Unlike RequestInterceptor, it
+// operates on the raw *http.Request/*http.Response, so it also sees requests a
+// RequestInterceptor can't, such as those of an http.Client shared with other code in the
+// process. Subscribe's WebSocket handshake is not among them: it is dialled through
+// Options.WebSocketDialer, not through an http.RoundTripper.
+type TransportInterceptor func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// NewInterceptedTransport wraps next with interceptors, in the order given: the first
+// interceptor is outermost and runs first. next defaults to http.DefaultTransport if nil.
+func NewInterceptedTransport(next http.RoundTripper, interceptors ...TransportInterceptor) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	rt := next
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		inner := rt
+		rt = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, inner)
+		})
+	}
+	return rt
+}
@@ -0,0 +1,125 @@
+package gqlclient
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans TracingInterceptor creates.
+const tracerName = "github.com/stefanprifti/gqlclient"
+
+// TracingInterceptor returns a RequestInterceptor that starts an OpenTelemetry span around
+// each request, named after its operation type ("query"/"mutation"), with the operation
+// type, the operation's name (when the query names one), and the query hash (not the full
+// query text, which may be large or contain sensitive literals) as attributes.
+// tracerProvider defaults to otel.GetTracerProvider() if nil.
+func TracingInterceptor(tracerProvider trace.TracerProvider) RequestInterceptor {
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	tracer := tracerProvider.Tracer(tracerName)
+
+	return func(ctx context.Context, req *Request, next func(context.Context, *Request) (*Response, error)) (*Response, error) {
+		attrs := []attribute.KeyValue{
+			attribute.String("graphql.operation", req.Operation),
+			attribute.String("graphql.query_hash", sha256Hash(req.Query)),
+		}
+		if name := operationName(req.Query); name != "" {
+			attrs = append(attrs, attribute.String("graphql.operation.name", name))
+		}
+		ctx, span := tracer.Start(ctx, req.Operation, trace.WithAttributes(attrs...))
+		defer span.End()
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return resp, err
+	}
+}
+
+// Logger is satisfied by *log.Logger and most other minimal logging libraries.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LoggingOptions configures LoggingInterceptor's redaction of sensitive fields.
+type LoggingOptions struct {
+	// RedactVariables, if true, logs "<redacted>" instead of a request's variables.
+	RedactVariables bool
+
+	// RedactHeaders lists Request.Headers names (case-insensitive) to log as
+	// "<redacted>" instead of their value, e.g. a per-request Authorization override.
+	RedactHeaders []string
+}
+
+// LoggingInterceptor returns a RequestInterceptor that logs each request's operation,
+// variables, and headers before it runs, and its success or failure after, via logger.
+func LoggingInterceptor(logger Logger, opts LoggingOptions) RequestInterceptor {
+	return func(ctx context.Context, req *Request, next func(context.Context, *Request) (*Response, error)) (*Response, error) {
+		variables := req.Variables
+		if opts.RedactVariables {
+			variables = "<redacted>"
+		}
+		logger.Printf("gqlclient: %s starting: variables=%v headers=%v", req.Operation, variables, redactHeaders(req.Headers, opts.RedactHeaders))
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			logger.Printf("gqlclient: %s failed: %v", req.Operation, err)
+		} else {
+			logger.Printf("gqlclient: %s succeeded", req.Operation)
+		}
+		return resp, err
+	}
+}
+
+func redactHeaders(headers map[string]string, redact []string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+
+	redacted := make(map[string]bool, len(redact))
+	for _, name := range redact {
+		redacted[canonicalHeaderKey(name)] = true
+	}
+
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if redacted[canonicalHeaderKey(k)] {
+			v = "<redacted>"
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func canonicalHeaderKey(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c - 'A' + 'a'
+		}
+	}
+	return string(b)
+}
+
+// HeaderInjector returns a RequestInterceptor that sets additional HTTP headers on every
+// request, e.g. a per-request X-Request-ID or tenant ID that the client's fixed
+// Authorization header doesn't accommodate. headers is called once per request, so it can
+// vary values like a generated request ID per call.
+func HeaderInjector(headers func(ctx context.Context) map[string]string) RequestInterceptor {
+	return func(ctx context.Context, req *Request, next func(context.Context, *Request) (*Response, error)) (*Response, error) {
+		if len(req.Headers) == 0 {
+			req.Headers = map[string]string{}
+		}
+		for k, v := range headers(ctx) {
+			req.Headers[k] = v
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,262 @@
+package gqlclient_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+func TestInterceptorsRunInOrderAndSeeHeaders(t *testing.T) {
+	var order []string
+	var gotAuthHeader string
+
+	trace := func(name string) gqlclient.RequestInterceptor {
+		return func(ctx context.Context, req *gqlclient.Request, next func(context.Context, *gqlclient.Request) (*gqlclient.Response, error)) (*gqlclient.Response, error) {
+			order = append(order, name+":before")
+			resp, err := next(ctx, req)
+			order = append(order, name+":after")
+			return resp, err
+		}
+	}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					gotAuthHeader = req.Header.Get("X-Tenant-ID")
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+					}, nil
+				},
+			},
+		},
+		Interceptors: []gqlclient.RequestInterceptor{
+			trace("outer"),
+			gqlclient.HeaderInjector(func(ctx context.Context) map[string]string {
+				return map[string]string{"X-Tenant-ID": "acme"}
+			}),
+			trace("inner"),
+		},
+	})
+
+	var resp struct{}
+	if err := client.Query(context.Background(), "query {}", map[string]interface{}{}, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantOrder := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("order = %v, want %v", order, wantOrder)
+	}
+	for i := range wantOrder {
+		if order[i] != wantOrder[i] {
+			t.Errorf("order[%d] = %q, want %q", i, order[i], wantOrder[i])
+		}
+	}
+
+	if gotAuthHeader != "acme" {
+		t.Errorf("X-Tenant-ID header = %q, want acme", gotAuthHeader)
+	}
+}
+
+func TestInterceptorsRunOnFileUploads(t *testing.T) {
+	var order []string
+	var gotTenantHeader string
+
+	trace := func(name string) gqlclient.RequestInterceptor {
+		return func(ctx context.Context, req *gqlclient.Request, next func(context.Context, *gqlclient.Request) (*gqlclient.Response, error)) (*gqlclient.Response, error) {
+			order = append(order, name+":before")
+			resp, err := next(ctx, req)
+			order = append(order, name+":after")
+			return resp, err
+		}
+	}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					gotTenantHeader = req.Header.Get("X-Tenant-ID")
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+					}, nil
+				},
+			},
+		},
+		Interceptors: []gqlclient.RequestInterceptor{
+			trace("outer"),
+			gqlclient.HeaderInjector(func(ctx context.Context) map[string]string {
+				return map[string]string{"X-Tenant-ID": "acme"}
+			}),
+		},
+	})
+
+	query := `mutation Upload($file: Upload!) { uploadFile(file: $file) { id } }`
+	variables := map[string]interface{}{"file": gqlclient.Upload{}}
+	files := map[string]io.Reader{"variables.file": strings.NewReader("file contents")}
+
+	var resp struct{}
+	if err := client.MutationWithFiles(context.Background(), query, variables, files, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantOrder := []string{"outer:before", "outer:after"}
+	if len(order) != len(wantOrder) {
+		t.Fatalf("order = %v, want %v", order, wantOrder)
+	}
+	for i := range wantOrder {
+		if order[i] != wantOrder[i] {
+			t.Errorf("order[%d] = %q, want %q", i, order[i], wantOrder[i])
+		}
+	}
+
+	if gotTenantHeader != "acme" {
+		t.Errorf("X-Tenant-ID header = %q, want acme", gotTenantHeader)
+	}
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestLoggingInterceptorRedaction(t *testing.T) {
+	logger := &testLogger{}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+					}, nil
+				},
+			},
+		},
+		Interceptors: []gqlclient.RequestInterceptor{
+			gqlclient.HeaderInjector(func(ctx context.Context) map[string]string {
+				return map[string]string{"Authorization": "Bearer secret"}
+			}),
+			gqlclient.LoggingInterceptor(logger, gqlclient.LoggingOptions{
+				RedactVariables: true,
+				RedactHeaders:   []string{"authorization"},
+			}),
+		},
+	})
+
+	var resp struct{}
+	if err := client.Query(context.Background(), "query {}", map[string]interface{}{"secret": "value"}, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %v", len(logger.lines), logger.lines)
+	}
+	if strings.Contains(logger.lines[0], "secret") || strings.Contains(logger.lines[0], "value") {
+		t.Errorf("expected redacted variables and headers, got %q", logger.lines[0])
+	}
+	if !strings.Contains(logger.lines[0], "<redacted>") {
+		t.Errorf("expected <redacted> placeholder, got %q", logger.lines[0])
+	}
+}
+
+// TestLoggingInterceptorRedactsClientAuthHeader covers the common case the request
+// describes: the client's own bearer token (set via TokenProvider, applied later in
+// setCommonHeaders, not via a HeaderInjector-style interceptor). do() mirrors that
+// resolved token into req.Headers before the interceptor chain runs specifically so a
+// RequestInterceptor like LoggingInterceptor can see and redact it.
+func TestLoggingInterceptorRedactsClientAuthHeader(t *testing.T) {
+	logger := &testLogger{}
+	attempts := 0
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts == 1 {
+						// First call: force the client to refresh its token via the
+						// TokenProvider path, same as real usage.
+						return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader(""))}, nil
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":{}}`))}, nil
+				},
+			},
+		},
+		TokenProvider: &mockTokenProvider{
+			getTokenFunc: func() (string, error) { return "secret-token", nil },
+		},
+		Interceptors: []gqlclient.RequestInterceptor{
+			gqlclient.LoggingInterceptor(logger, gqlclient.LoggingOptions{
+				RedactHeaders: []string{"authorization"},
+			}),
+		},
+	})
+
+	var resp struct{}
+	// First call: triggers the 401 -> refresh -> retry path and populates c.token.
+	if err := client.Query(context.Background(), "query {}", map[string]interface{}{}, &resp); err != nil {
+		t.Fatalf("unexpected error priming the token: %v", err)
+	}
+	// Second call: the client now has a token to send, the same way every request after
+	// the first would in real usage.
+	if err := client.Query(context.Background(), "query {}", map[string]interface{}{}, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.lines) != 4 {
+		t.Fatalf("expected 4 log lines, got %d: %v", len(logger.lines), logger.lines)
+	}
+
+	secondCallStart := logger.lines[2]
+	if strings.Contains(secondCallStart, "secret-token") {
+		t.Errorf("expected the client's own bearer token to be redacted, got %q", secondCallStart)
+	}
+	if !strings.Contains(secondCallStart, "<redacted>") {
+		t.Errorf("expected <redacted> placeholder in %q", secondCallStart)
+	}
+}
+
+func TestNewInterceptedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var order []string
+	mark := func(name string) gqlclient.TransportInterceptor {
+		return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			order = append(order, name)
+			return next.RoundTrip(req)
+		}
+	}
+
+	transport := gqlclient.NewInterceptedTransport(http.DefaultTransport, mark("first"), mark("second"))
+
+	httpClient := &http.Client{Transport: transport}
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("order = %v, want [first second]", order)
+	}
+}
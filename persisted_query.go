@@ -0,0 +1,225 @@
+package gqlclient
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultPersistedQueryCacheSize is the number of query hashes cached in memory.
+const defaultPersistedQueryCacheSize = 256
+
+// persistedQueryNotFoundCode is the extensions.code value a spec-compliant server
+// returns when an APQ hash has not been registered yet.
+const persistedQueryNotFoundCode = "PERSISTED_QUERY_NOT_FOUND"
+
+// persistedQueryExtensions is the `extensions.persistedQuery` field of an APQ request.
+type persistedQueryExtensions struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// requestExtensions is the `extensions` field of an APQ request.
+type requestExtensions struct {
+	PersistedQuery persistedQueryExtensions `json:"persistedQuery"`
+}
+
+// persistedQueryRequest is the JSON body of an APQ request.
+type persistedQueryRequest struct {
+	Query      string      `json:"query,omitempty"`
+	Variables  interface{} `json:"variables"`
+	Extensions interface{} `json:"extensions"`
+}
+
+// queryHashCache is a fixed-size LRU cache mapping a query string to its SHA-256 hash.
+type queryHashCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type queryHashEntry struct {
+	query string
+	hash  string
+}
+
+// sha256Hash returns the hex-encoded SHA-256 hash of query.
+func sha256Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func newQueryHashCache(capacity int) *queryHashCache {
+	return &queryHashCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// hash returns the SHA-256 hash of query, computing and caching it on a miss.
+func (c *queryHashCache) hash(query string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[query]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*queryHashEntry).hash
+	}
+
+	hash := sha256Hash(query)
+
+	elem := c.order.PushFront(&queryHashEntry{query: query, hash: hash})
+	c.entries[query] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*queryHashEntry).query)
+		}
+	}
+
+	return hash
+}
+
+// doPersisted executes req using the automatic persisted queries protocol: it first sends
+// only the query hash, and on a PERSISTED_QUERY_NOT_FOUND error retries once with the full
+// query included so the server can register it. GET is only used for queries, never
+// mutations, regardless of c.usePersistedQueriesOverGET: a GET must be side-effect free.
+func (c *Client) doPersisted(ctx context.Context, req *Request, data interface{}) error {
+	ext := requestExtensions{
+		PersistedQuery: persistedQueryExtensions{
+			Version:    1,
+			Sha256Hash: c.apqCache.hash(req.Query),
+		},
+	}
+
+	var err error
+	if c.usePersistedQueriesOverGET && req.Operation == "query" {
+		err = c.doPersistedGET(ctx, req, ext, data)
+	} else {
+		err = c.doPersistedPOST(ctx, req, ext, false, data)
+	}
+
+	if isPersistedQueryNotFound(err) {
+		return c.doPersistedPOST(ctx, req, ext, true, data)
+	}
+	return err
+}
+
+func (c *Client) doPersistedPOST(ctx context.Context, req *Request, ext requestExtensions, includeQuery bool, data interface{}) error {
+	body := persistedQueryRequest{
+		Variables:  req.Variables,
+		Extensions: ext,
+	}
+	if includeQuery {
+		body.Query = req.Query
+	}
+
+	jsonReq, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal persisted query request: %w", err)
+	}
+
+	build := func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(jsonReq))
+		if err != nil {
+			return nil, err
+		}
+		c.setCommonHeaders(httpReq, req)
+		return httpReq, nil
+	}
+
+	return c.doHTTP(ctx, req, build, data)
+}
+
+func (c *Client) doPersistedGET(ctx context.Context, req *Request, ext requestExtensions, data interface{}) error {
+	extJSON, err := json.Marshal(ext)
+	if err != nil {
+		return fmt.Errorf("failed to marshal persisted query extensions: %w", err)
+	}
+	varsJSON, err := json.Marshal(req.Variables)
+	if err != nil {
+		return fmt.Errorf("failed to marshal variables: %w", err)
+	}
+
+	q := url.Values{}
+	q.Set("extensions", string(extJSON))
+	q.Set("variables", string(varsJSON))
+	if name := operationName(req.Query); name != "" {
+		q.Set("operationName", name)
+	}
+
+	build := func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setCommonHeaders(httpReq, req)
+		return httpReq, nil
+	}
+
+	return c.doHTTP(ctx, req, build, data)
+}
+
+// operationName extracts the optional operation name from a GraphQL query document, e.g.
+// "GetCountry" from "query GetCountry($code: ID!) { ... }". It returns "" for anonymous
+// operations (e.g. "query { ... }") or documents it cannot confidently parse; req.Operation
+// is always just "query"/"mutation" and never the operation's name, so doPersistedGET needs
+// this to populate operationName for multi-operation documents.
+func operationName(query string) string {
+	trimmed := strings.TrimSpace(query)
+	for _, kw := range []string{"query", "mutation", "subscription"} {
+		if !strings.HasPrefix(trimmed, kw) {
+			continue
+		}
+		rest := strings.TrimSpace(trimmed[len(kw):])
+		first, _ := utf8.DecodeRuneInString(rest)
+		if rest == "" || !isNameStart(first) {
+			return ""
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool { return !isNameChar(r) })
+		if end == -1 {
+			return rest
+		}
+		return rest[:end]
+	}
+	return ""
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isPersistedQueryNotFound reports whether err contains a GraphQL error with
+// extensions.code == PERSISTED_QUERY_NOT_FOUND.
+func isPersistedQueryNotFound(err error) bool {
+	errs, ok := err.(Errors)
+	if !ok {
+		return false
+	}
+
+	for i := range errs {
+		if errs[i].Code() == persistedQueryNotFoundCode {
+			return true
+		}
+	}
+
+	return false
+}
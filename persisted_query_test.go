@@ -0,0 +1,168 @@
+package gqlclient_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+func TestQueryWithPersistedQueries(t *testing.T) {
+	query := `query Country($code: ID!) { country(code: $code) { code } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	attempts := 0
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read request body: %v", err)
+					}
+
+					var decoded struct {
+						Query      string `json:"query"`
+						Extensions struct {
+							PersistedQuery struct {
+								Version    int    `json:"version"`
+								Sha256Hash string `json:"sha256Hash"`
+							} `json:"persistedQuery"`
+						} `json:"extensions"`
+					}
+					if err := json.Unmarshal(body, &decoded); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+
+					if decoded.Extensions.PersistedQuery.Sha256Hash != hash {
+						t.Errorf("expected hash %q, got %q", hash, decoded.Extensions.PersistedQuery.Sha256Hash)
+					}
+
+					if attempts == 1 {
+						if decoded.Query != "" {
+							t.Errorf("expected first attempt to omit the query, got %q", decoded.Query)
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body: io.NopCloser(strings.NewReader(`
+								{
+									"errors": [
+										{"message": "PersistedQueryNotFound", "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}
+									]
+								}`)),
+						}, nil
+					}
+
+					if decoded.Query != query {
+						t.Errorf("expected retry to include the full query, got %q", decoded.Query)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"country": {"code": "AL"}}}`)),
+					}, nil
+				},
+			},
+		},
+		EnablePersistedQueries: true,
+	})
+
+	resp := struct {
+		Country struct {
+			Code string `json:"code"`
+		} `json:"country"`
+	}{}
+
+	err := client.Query(context.Background(), query, map[string]interface{}{"code": "AL"}, &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+
+	if resp.Country.Code != "AL" {
+		t.Errorf("expected country code %q, got %q", "AL", resp.Country.Code)
+	}
+}
+
+func TestMutationWithPersistedQueriesOverGETStillUsesPOST(t *testing.T) {
+	query := `mutation SetCountry($code: ID!) { setCountry(code: $code) { code } }`
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					if req.Method != http.MethodPost {
+						t.Errorf("expected a mutation to be sent over POST even with UsePersistedQueriesOverGET, got %s", req.Method)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"setCountry": {"code": "AL"}}}`)),
+					}, nil
+				},
+			},
+		},
+		EnablePersistedQueries:     true,
+		UsePersistedQueriesOverGET: true,
+	})
+
+	resp := struct {
+		SetCountry struct {
+			Code string `json:"code"`
+		} `json:"setCountry"`
+	}{}
+
+	err := client.Mutation(context.Background(), query, map[string]interface{}{"code": "AL"}, &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestQueryWithPersistedQueriesOverGETSendsOperationName(t *testing.T) {
+	query := `query GetCountry($code: ID!) { country(code: $code) { code } }`
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					if req.Method != http.MethodGet {
+						t.Errorf("expected a GET request, got %s", req.Method)
+					}
+					if got := req.URL.Query().Get("operationName"); got != "GetCountry" {
+						t.Errorf("expected operationName %q, got %q", "GetCountry", got)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"country": {"code": "AL"}}}`)),
+					}, nil
+				},
+			},
+		},
+		EnablePersistedQueries:     true,
+		UsePersistedQueriesOverGET: true,
+	})
+
+	resp := struct {
+		Country struct {
+			Code string `json:"code"`
+		} `json:"country"`
+	}{}
+
+	err := client.Query(context.Background(), query, map[string]interface{}{"code": "AL"}, &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package gqlclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request should be retried, and how long to wait
+// before doing so. attempt is the number of retries already made (0 on the first retry
+// decision). resp is nil when err is a transport-level error (no response was received).
+type RetryPolicy interface {
+	ShouldRetry(attempt int, req *Request, resp *http.Response, err error) (retry bool, wait time.Duration)
+}
+
+// ExponentialBackoffRetryPolicy retries network errors, 401, and 5xx responses with
+// exponential backoff and jitter, honoring Retry-After on 429 and 503 responses. 401 is
+// retried so a refreshed TokenProvider token gets a chance to be used, but is still bounded
+// by MaxAttempts like any other status; doHTTP only consults ShouldRetry for a 401 when the
+// client has a TokenProvider; otherwise it fails immediately without a retry. Other 4xx
+// responses are not retried.
+type ExponentialBackoffRetryPolicy struct {
+	// MaxAttempts caps the number of retries. Defaults to DefaultRetryCount if zero.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Defaults to 100ms if zero.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. Defaults to 10s if zero.
+	MaxDelay time.Duration
+}
+
+// NewExponentialBackoffRetryPolicy returns an ExponentialBackoffRetryPolicy configured with
+// the package defaults.
+func NewExponentialBackoffRetryPolicy() *ExponentialBackoffRetryPolicy {
+	return &ExponentialBackoffRetryPolicy{}
+}
+
+func (p *ExponentialBackoffRetryPolicy) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return DefaultRetryCount
+}
+
+func (p *ExponentialBackoffRetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay > 0 {
+		return p.BaseDelay
+	}
+	return 100 * time.Millisecond
+}
+
+func (p *ExponentialBackoffRetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return 10 * time.Second
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p *ExponentialBackoffRetryPolicy) ShouldRetry(attempt int, req *Request, resp *http.Response, err error) (bool, time.Duration) {
+	if attempt >= p.maxAttempts() {
+		return false, 0
+	}
+
+	if err != nil {
+		return true, p.backoff(attempt)
+	}
+
+	if resp == nil {
+		return false, 0
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if wait, ok := retryAfter(resp); ok {
+			return true, wait
+		}
+		return true, p.backoff(attempt)
+	case http.StatusUnauthorized:
+		return true, p.backoff(attempt)
+	}
+
+	if resp.StatusCode >= 500 {
+		return true, p.backoff(attempt)
+	}
+
+	return false, 0
+}
+
+// backoff returns the delay before the given attempt's retry: a doubling base delay, capped
+// at maxDelay, with up to 20% jitter to avoid thundering-herd retries.
+func (p *ExponentialBackoffRetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay() << attempt
+	if max := p.maxDelay(); delay > max {
+		delay = max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay + jitter
+}
+
+// sleep waits for d, returning early with ctx.Err() if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header expressed as a number of seconds.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
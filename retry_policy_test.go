@@ -0,0 +1,155 @@
+package gqlclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+func TestQueryRetriesOn5xx(t *testing.T) {
+	attempts := 0
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts < 3 {
+						return &http.Response{
+							StatusCode: http.StatusServiceUnavailable,
+							Header:     http.Header{},
+							Body:       io.NopCloser(strings.NewReader("")),
+						}, nil
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"country": {"code": "AL"}}}`)),
+					}, nil
+				},
+			},
+		},
+		RetryPolicy: &gqlclient.ExponentialBackoffRetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    time.Millisecond,
+		},
+	})
+
+	resp := struct {
+		Country struct {
+			Code string `json:"code"`
+		} `json:"country"`
+	}{}
+
+	err := client.Query(context.Background(), `query { country { code } }`, map[string]interface{}{}, &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	if resp.Country.Code != "AL" {
+		t.Errorf("expected country code %q, got %q", "AL", resp.Country.Code)
+	}
+}
+
+func TestQueryBounds401Retries(t *testing.T) {
+	attempts := 0
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return &http.Response{
+						StatusCode: http.StatusUnauthorized,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			},
+		},
+		TokenProvider: &mockTokenProvider{
+			getTokenFunc: func() (string, error) {
+				return "token", nil
+			},
+		},
+		RetryPolicy: &gqlclient.ExponentialBackoffRetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    time.Millisecond,
+		},
+	})
+
+	resp := struct{}{}
+	err := client.Query(context.Background(), `query { country { code } }`, map[string]interface{}{}, &resp)
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted, got nil")
+	}
+
+	if attempts != 4 {
+		t.Errorf("expected the retry policy's MaxAttempts to bound a persistent 401 to 4 attempts (1 initial + 3 retries), got %d", attempts)
+	}
+}
+
+func TestQueryDoesNotRetry401WithoutTokenProvider(t *testing.T) {
+	attempts := 0
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return &http.Response{
+						StatusCode: http.StatusUnauthorized,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			},
+		},
+	})
+
+	resp := struct{}{}
+	err := client.Query(context.Background(), `query { country { code } }`, map[string]interface{}{}, &resp)
+	if err == nil {
+		t.Fatal("expected an error for a 401 response, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("expected no retries for a 401 without a TokenProvider, got %d attempts", attempts)
+	}
+}
+
+func TestQueryDoesNotRetryOn4xx(t *testing.T) {
+	attempts := 0
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			},
+		},
+	})
+
+	resp := struct{}{}
+	err := client.Query(context.Background(), `query { country { code } }`, map[string]interface{}{}, &resp)
+	if err == nil {
+		t.Fatal("expected an error for a 400 response, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("expected no retries for a non-retryable status, got %d attempts", attempts)
+	}
+}
@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+// introspectionQuery is the standard GraphQL introspection query, trimmed to the fields
+// cmd/gqlclientgen needs to generate types: root operation names, and every named type's
+// kind, fields, input fields, and enum values.
+const introspectionQuery = `
+	query IntrospectSchema {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			types {
+				kind
+				name
+				fields(includeDeprecated: true) {
+					name
+					type { ...TypeRef }
+				}
+				inputFields {
+					name
+					type { ...TypeRef }
+				}
+				enumValues(includeDeprecated: true) {
+					name
+				}
+			}
+		}
+	}
+
+	fragment TypeRef on __Type {
+		kind
+		name
+		ofType {
+			kind
+			name
+			ofType {
+				kind
+				name
+				ofType {
+					kind
+					name
+					ofType {
+						kind
+						name
+					}
+				}
+			}
+		}
+	}`
+
+// namedRef is the `{name: "..."}` shape used for queryType/mutationType/subscriptionType.
+type namedRef struct {
+	Name string `json:"name"`
+}
+
+type introspectionResult struct {
+	Schema struct {
+		QueryType        *namedRef    `json:"queryType"`
+		MutationType     *namedRef    `json:"mutationType"`
+		SubscriptionType *namedRef    `json:"subscriptionType"`
+		Types            []*NamedType `json:"types"`
+	} `json:"__schema"`
+}
+
+// Introspect issues the standard introspection query against a live GraphQL endpoint and
+// returns the resulting Schema, for bootstrapping a schema file to check into the repo.
+func Introspect(ctx context.Context, endpoint string) (*Schema, error) {
+	client := gqlclient.New(gqlclient.Options{Endpoint: endpoint})
+
+	var result introspectionResult
+	if err := client.Query(ctx, introspectionQuery, map[string]interface{}{}, &result); err != nil {
+		return nil, fmt.Errorf("failed to run introspection query: %w", err)
+	}
+
+	return buildSchema(&result), nil
+}
+
+// ParseIntrospectionJSON loads a Schema from a previously saved introspection response, in
+// the `{"__schema": {...}}` shape Introspect itself produces.
+func ParseIntrospectionJSON(r io.Reader) (*Schema, error) {
+	var result introspectionResult
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection JSON: %w", err)
+	}
+
+	return buildSchema(&result), nil
+}
+
+// MarshalIntrospectionJSON serializes s back into the `{"__schema": {...}}` shape Introspect
+// produces, so it can be checked into the repo and loaded later via ParseIntrospectionJSON.
+func (s *Schema) MarshalIntrospectionJSON() ([]byte, error) {
+	var result introspectionResult
+	if s.QueryType != "" {
+		result.Schema.QueryType = &namedRef{Name: s.QueryType}
+	}
+	if s.MutationType != "" {
+		result.Schema.MutationType = &namedRef{Name: s.MutationType}
+	}
+	if s.SubscriptionType != "" {
+		result.Schema.SubscriptionType = &namedRef{Name: s.SubscriptionType}
+	}
+
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Schema.Types = append(result.Schema.Types, s.Types[name])
+	}
+
+	return json.MarshalIndent(&result, "", "  ")
+}
+
+func buildSchema(result *introspectionResult) *Schema {
+	s := &Schema{Types: make(map[string]*NamedType, len(result.Schema.Types))}
+
+	if result.Schema.QueryType != nil {
+		s.QueryType = result.Schema.QueryType.Name
+	}
+	if result.Schema.MutationType != nil {
+		s.MutationType = result.Schema.MutationType.Name
+	}
+	if result.Schema.SubscriptionType != nil {
+		s.SubscriptionType = result.Schema.SubscriptionType.Name
+	}
+
+	for _, t := range result.Schema.Types {
+		s.Types[t.Name] = t
+	}
+
+	return s
+}
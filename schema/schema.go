@@ -0,0 +1,110 @@
+// Package schema represents a GraphQL schema loaded from introspection JSON or SDL, and is
+// consumed by cmd/gqlclientgen to generate typed Go operations.
+package schema
+
+import "fmt"
+
+// TypeRef is a (possibly wrapped) reference to a named type, e.g. `[String!]!`.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// NonNull reports whether the reference is wrapped in NON_NULL.
+func (t *TypeRef) NonNull() bool {
+	return t != nil && t.Kind == "NON_NULL"
+}
+
+// List reports whether the reference is (possibly through NON_NULL) a LIST.
+func (t *TypeRef) List() bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind == "LIST" {
+		return true
+	}
+	if t.Kind == "NON_NULL" {
+		return t.OfType.List()
+	}
+	return false
+}
+
+// NamedType returns the innermost named type a TypeRef wraps, unwrapping NON_NULL and LIST.
+func (t *TypeRef) NamedType() string {
+	if t == nil {
+		return ""
+	}
+	if t.OfType != nil {
+		return t.OfType.NamedType()
+	}
+	return t.Name
+}
+
+// Field is a field on an object or interface type.
+type Field struct {
+	Name string   `json:"name"`
+	Type *TypeRef `json:"type"`
+}
+
+// InputValue is a field on an input object, or an argument to a field.
+type InputValue struct {
+	Name string   `json:"name"`
+	Type *TypeRef `json:"type"`
+}
+
+// EnumValue is one member of an enum type.
+type EnumValue struct {
+	Name string `json:"name"`
+}
+
+// NamedType is a type defined in the schema: an object, interface, input object, enum, or
+// scalar. LIST and NON_NULL wrappers are represented by TypeRef instead.
+type NamedType struct {
+	Kind        string       `json:"kind"`
+	Name        string       `json:"name"`
+	Fields      []Field      `json:"fields"`
+	InputFields []InputValue `json:"inputFields"`
+	EnumValues  []EnumValue  `json:"enumValues"`
+}
+
+// FieldByName returns the named field, or nil if t has no such field.
+func (t *NamedType) FieldByName(name string) *Field {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Schema is a parsed GraphQL schema: its root operation types plus every named type it
+// defines, as loaded from introspection JSON (Introspect) or SDL (ParseSDL).
+type Schema struct {
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*NamedType
+}
+
+// RootType returns the object type backing the given root operation ("query", "mutation",
+// or "subscription").
+func (s *Schema) RootType(operation string) (*NamedType, error) {
+	var name string
+	switch operation {
+	case "query":
+		name = s.QueryType
+	case "mutation":
+		name = s.MutationType
+	case "subscription":
+		name = s.SubscriptionType
+	default:
+		return nil, fmt.Errorf("unknown operation type %q", operation)
+	}
+
+	t, ok := s.Types[name]
+	if !ok {
+		return nil, fmt.Errorf("schema has no root %s type", operation)
+	}
+	return t, nil
+}
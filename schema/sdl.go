@@ -0,0 +1,340 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ParseSDL loads a Schema from GraphQL SDL. It supports scalar, type, interface, input, and
+// enum definitions; directives and union member lists are parsed but not interpreted, since
+// cmd/gqlclientgen only needs field and input shapes to generate Go types.
+func ParseSDL(r io.Reader) (*Schema, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SDL: %w", err)
+	}
+
+	p := &sdlParser{tokens: tokenizeSDL(string(b))}
+	s := &Schema{Types: make(map[string]*NamedType)}
+
+	for !p.atEnd() {
+		if err := p.parseDefinition(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.QueryType == "" {
+		if _, ok := s.Types["Query"]; ok {
+			s.QueryType = "Query"
+		}
+	}
+	if s.MutationType == "" {
+		if _, ok := s.Types["Mutation"]; ok {
+			s.MutationType = "Mutation"
+		}
+	}
+	if s.SubscriptionType == "" {
+		if _, ok := s.Types["Subscription"]; ok {
+			s.SubscriptionType = "Subscription"
+		}
+	}
+
+	return s, nil
+}
+
+// tokenizeSDL splits GraphQL SDL into punctuation and word tokens, dropping comments and
+// string/block-string values (neither of which this subset of the parser needs).
+func tokenizeSDL(src string) []string {
+	var tokens []string
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			i++
+			if i+1 < len(runes) && runes[i] == '"' && runes[i+1] == '"' {
+				i += 2
+				for i+2 < len(runes) && !(runes[i] == '"' && runes[i+1] == '"' && runes[i+2] == '"') {
+					i++
+				}
+				i += 2
+			} else {
+				for i < len(runes) && runes[i] != '"' {
+					if runes[i] == '\\' {
+						i++
+					}
+					i++
+				}
+			}
+		case strings.ContainsRune("{}()[]:!=|&@,", r):
+			tokens = append(tokens, string(r))
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("{}()[]:!=|&@,#\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+			i--
+		}
+	}
+
+	return tokens
+}
+
+type sdlParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *sdlParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *sdlParser) peek() string {
+	if p.atEnd() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *sdlParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// skipBalanced consumes a balanced (...) or [...] group, assuming the opening token has
+// already been consumed by the caller.
+func (p *sdlParser) skipBalanced(open, close string) {
+	depth := 1
+	for depth > 0 && !p.atEnd() {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+		}
+	}
+}
+
+// skipDirectives consumes any `@name(...)` directives at the current position.
+func (p *sdlParser) skipDirectives() {
+	for p.peek() == "@" {
+		p.next()
+		p.next() // directive name
+		if p.peek() == "(" {
+			p.next()
+			p.skipBalanced("(", ")")
+		}
+	}
+}
+
+func (p *sdlParser) parseDefinition(s *Schema) error {
+	switch p.next() {
+	case "schema":
+		return p.parseSchemaBlock(s)
+	case "scalar":
+		name := p.next()
+		p.skipDirectives()
+		s.Types[name] = &NamedType{Kind: "SCALAR", Name: name}
+	case "type":
+		return p.parseObjectLike(s, "OBJECT")
+	case "interface":
+		return p.parseObjectLike(s, "INTERFACE")
+	case "input":
+		return p.parseObjectLike(s, "INPUT_OBJECT")
+	case "enum":
+		return p.parseEnum(s)
+	case "union":
+		return p.parseUnion(s)
+	case "extend":
+		// Skip extensions; the base definition already carries the fields we need.
+		p.next()
+		p.next()
+		p.skipDirectives()
+		if p.peek() == "{" {
+			p.next()
+			p.skipBalanced("{", "}")
+		}
+	case "directive":
+		for !p.atEnd() && p.peek() != "on" {
+			if p.peek() == "(" {
+				p.next()
+				p.skipBalanced("(", ")")
+				continue
+			}
+			p.next()
+		}
+		if p.peek() == "on" {
+			p.next()
+			for !p.atEnd() && p.peek() != "|" && !isKeyword(p.peek()) {
+				p.next()
+			}
+		}
+	default:
+		// Unrecognized top-level token; skip it to stay resilient to schema features this
+		// generator doesn't need (e.g. "implements X & Y" already consumed elsewhere).
+	}
+
+	return nil
+}
+
+func isKeyword(tok string) bool {
+	switch tok {
+	case "schema", "scalar", "type", "interface", "input", "enum", "union", "extend", "directive":
+		return true
+	}
+	return false
+}
+
+func (p *sdlParser) parseSchemaBlock(s *Schema) error {
+	p.skipDirectives()
+	if p.next() != "{" {
+		return fmt.Errorf("expected '{' after schema")
+	}
+
+	for p.peek() != "}" && !p.atEnd() {
+		op := p.next()
+		if p.next() != ":" {
+			return fmt.Errorf("expected ':' in schema block")
+		}
+		name := p.next()
+		switch op {
+		case "query":
+			s.QueryType = name
+		case "mutation":
+			s.MutationType = name
+		case "subscription":
+			s.SubscriptionType = name
+		}
+	}
+	p.next() // "}"
+
+	return nil
+}
+
+// parseObjectLike parses `type`/`interface`/`input` definitions, which share the same
+// `Name [implements ...] [directives] { fields }` shape (input fields omit arguments).
+func (p *sdlParser) parseObjectLike(s *Schema, kind string) error {
+	name := p.next()
+
+	if p.peek() == "implements" {
+		p.next()
+		for !p.atEnd() && p.peek() != "{" && p.peek() != "@" {
+			p.next()
+		}
+	}
+	p.skipDirectives()
+
+	t := &NamedType{Kind: kind, Name: name}
+
+	if p.peek() == "{" {
+		p.next()
+		for p.peek() != "}" && !p.atEnd() {
+			fieldName := p.next()
+
+			if p.peek() == "(" {
+				p.next()
+				p.skipBalanced("(", ")")
+			}
+
+			if p.next() != ":" {
+				return fmt.Errorf("expected ':' after field %q in %q", fieldName, name)
+			}
+
+			typeRef, err := p.parseTypeRef()
+			if err != nil {
+				return err
+			}
+
+			if p.peek() == "=" {
+				p.next()
+				p.next() // default value, not needed for codegen
+			}
+			p.skipDirectives()
+
+			if kind == "INPUT_OBJECT" {
+				t.InputFields = append(t.InputFields, InputValue{Name: fieldName, Type: typeRef})
+			} else {
+				t.Fields = append(t.Fields, Field{Name: fieldName, Type: typeRef})
+			}
+		}
+		p.next() // "}"
+	}
+
+	s.Types[name] = t
+	return nil
+}
+
+func (p *sdlParser) parseEnum(s *Schema) error {
+	name := p.next()
+	p.skipDirectives()
+
+	t := &NamedType{Kind: "ENUM", Name: name}
+
+	if p.peek() == "{" {
+		p.next()
+		for p.peek() != "}" && !p.atEnd() {
+			value := p.next()
+			p.skipDirectives()
+			t.EnumValues = append(t.EnumValues, EnumValue{Name: value})
+		}
+		p.next() // "}"
+	}
+
+	s.Types[name] = t
+	return nil
+}
+
+func (p *sdlParser) parseUnion(s *Schema) error {
+	name := p.next()
+	p.skipDirectives()
+
+	t := &NamedType{Kind: "UNION", Name: name}
+	if p.peek() == "=" {
+		p.next()
+		for !p.atEnd() && !isKeyword(p.peek()) {
+			p.next()
+		}
+	}
+
+	s.Types[name] = t
+	return nil
+}
+
+// parseTypeRef parses a (possibly wrapped) type reference, e.g. `[String!]!`.
+func (p *sdlParser) parseTypeRef() (*TypeRef, error) {
+	if p.peek() == "[" {
+		p.next()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != "]" {
+			return nil, fmt.Errorf("expected ']' to close list type")
+		}
+
+		ref := &TypeRef{Kind: "LIST", OfType: inner}
+		if p.peek() == "!" {
+			p.next()
+			ref = &TypeRef{Kind: "NON_NULL", OfType: ref}
+		}
+		return ref, nil
+	}
+
+	name := p.next()
+	ref := &TypeRef{Kind: "NAMED", Name: name}
+	if p.peek() == "!" {
+		p.next()
+		ref = &TypeRef{Kind: "NON_NULL", OfType: ref}
+	}
+	return ref, nil
+}
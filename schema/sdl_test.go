@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSDL(t *testing.T) {
+	const sdl = `
+	schema {
+		query: Query
+	}
+
+	enum Status {
+		ACTIVE
+		INACTIVE
+	}
+
+	type Country {
+		code: String!
+		name: String
+		status: Status
+	}
+
+	type Query {
+		countries: [Country!]!
+	}
+	`
+
+	s, err := ParseSDL(strings.NewReader(sdl))
+	if err != nil {
+		t.Fatalf("ParseSDL: %v", err)
+	}
+
+	if s.QueryType != "Query" {
+		t.Errorf("QueryType = %q, want Query", s.QueryType)
+	}
+
+	root, err := s.RootType("query")
+	if err != nil {
+		t.Fatalf("RootType: %v", err)
+	}
+
+	field := root.FieldByName("countries")
+	if field == nil {
+		t.Fatal("Query has no field \"countries\"")
+	}
+	if !field.Type.NonNull() || !field.Type.List() {
+		t.Errorf("countries type = %+v, want NON_NULL LIST", field.Type)
+	}
+	if got := field.Type.NamedType(); got != "Country" {
+		t.Errorf("countries named type = %q, want Country", got)
+	}
+
+	status, ok := s.Types["Status"]
+	if !ok || status.Kind != "ENUM" || len(status.EnumValues) != 2 {
+		t.Errorf("Status = %+v, want ENUM with 2 values", status)
+	}
+}
+
+func TestParseSDLInfersRootTypesWithoutSchemaBlock(t *testing.T) {
+	const sdl = `
+	type Query {
+		ping: String!
+	}
+
+	type Mutation {
+		ping: String!
+	}
+	`
+
+	s, err := ParseSDL(strings.NewReader(sdl))
+	if err != nil {
+		t.Fatalf("ParseSDL: %v", err)
+	}
+
+	if s.QueryType != "Query" {
+		t.Errorf("QueryType = %q, want Query", s.QueryType)
+	}
+	if s.MutationType != "Mutation" {
+		t.Errorf("MutationType = %q, want Mutation", s.MutationType)
+	}
+}
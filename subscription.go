@@ -0,0 +1,236 @@
+package gqlclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// graphQLTransportWSProtocol is the WebSocket subprotocol implemented by Subscribe.
+const graphQLTransportWSProtocol = "graphql-transport-ws"
+
+// message types for the graphql-transport-ws protocol.
+const (
+	msgTypeConnectionInit = "connection_init"
+	msgTypeConnectionAck  = "connection_ack"
+	msgTypeSubscribe      = "subscribe"
+	msgTypeNext           = "next"
+	msgTypeError          = "error"
+	msgTypeComplete       = "complete"
+	msgTypePing           = "ping"
+	msgTypePong           = "pong"
+)
+
+// wsMessage is an envelope for graphql-transport-ws protocol messages.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// subscribePayload is the payload of a "subscribe" message. Unlike Request, it serializes
+// operationName: the server needs it to disambiguate a multi-operation document, and
+// Request can't supply it since Request.Operation is just "query"/"mutation"/"subscription"
+// and is tagged json:"-" anyway.
+type subscribePayload struct {
+	Query         string      `json:"query"`
+	Variables     interface{} `json:"variables"`
+	OperationName string      `json:"operationName,omitempty"`
+}
+
+// SubscriptionHandler receives messages delivered by a subscription started with Subscribe.
+// It is called once per "next" message, and a final time with a non-nil err when the
+// subscription ends, either because the server sent a "complete"/"error" frame, the
+// connection failed, or the context was cancelled. Once the "subscribe" message has been
+// sent, a caller that needs to release a resource (close a channel, signal a WaitGroup) when
+// the subscription ends can rely on this final call happening on every termination path. A
+// failure before that point (dialing, connection_init, or waiting for connection_ack, including
+// ctx cancellation during that wait) is only returned by Subscribe; handler is not called.
+// Subscribe returns the same err as the final call, or nil when that err is
+// ErrSubscriptionComplete, so callers don't need to correlate it with any non-nil err passed
+// to an earlier, non-final call (e.g. one reporting an undecodable "next" payload).
+type SubscriptionHandler func(resp *Response, err error)
+
+// ErrSubscriptionComplete is the error SubscriptionHandler's final call receives when the
+// subscription ends because the server sent a graceful "complete" (or an empty "error")
+// frame, as opposed to a network failure, ctx cancellation, or a server-reported GraphQL
+// error.
+var ErrSubscriptionComplete = errors.New("gqlclient: subscription complete")
+
+var subscriptionIDCounter int64
+
+func nextSubscriptionID() string {
+	return strconv.FormatInt(atomic.AddInt64(&subscriptionIDCounter, 1), 10)
+}
+
+// Subscribe opens a WebSocket connection to the client's endpoint and executes a GraphQL
+// subscription using the graphql-transport-ws protocol. handler is invoked for every value
+// delivered by the subscription; it is invoked a final time with a non-nil error when the
+// subscription terminates (server completion, server error, or ctx cancellation). Failures
+// before the "subscribe" message is sent are returned without calling handler.
+//
+// Subscribe blocks until the subscription ends. Callers that want to run it in the
+// background should invoke it in its own goroutine.
+func (c *Client) Subscribe(ctx context.Context, q string, v interface{}, handler SubscriptionHandler) error {
+	if err := validateOperationVariables(v); err != nil {
+		return fmt.Errorf("failed to validate operation variables: %w", err)
+	}
+
+	dialer := c.webSocketDialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	header := make(map[string][]string)
+	if len(dialer.Subprotocols) == 0 {
+		header["Sec-Websocket-Protocol"] = []string{graphQLTransportWSProtocol}
+	}
+	if c.token != "" {
+		header["Authorization"] = []string{"Bearer " + c.token}
+	}
+
+	conn, _, err := dialer.DialContext(ctx, wsEndpoint(c.endpoint), header)
+	if err != nil {
+		return fmt.Errorf("failed to dial websocket: %w", err)
+	}
+	defer conn.Close()
+
+	// writeMu serializes writes to conn: the ctx-cancellation goroutine below and the
+	// main read loop (replying to "ping" with "pong") can both write concurrently, and
+	// gorilla/websocket allows only one writer at a time.
+	var writeMu sync.Mutex
+	writeJSON := func(msg *wsMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(msg)
+	}
+
+	id := nextSubscriptionID()
+
+	// done signals the watcher goroutine below to exit when Subscribe returns through
+	// any path other than ctx cancellation (a server "complete"/"error" frame, a read
+	// error); otherwise it would leak, blocked on <-ctx.Done(), for as long as ctx
+	// outlives the subscription. It's started here, before connection_init is even
+	// sent, so a peer that accepts the WS upgrade but never replies (to connection_init
+	// or, later, subscribe) still has its blocking ReadJSON unblocked by ctx
+	// cancellation rather than hanging forever.
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = writeJSON(&wsMessage{ID: id, Type: msgTypeComplete})
+			// Unblock whatever conn.ReadJSON is currently blocked on (the
+			// connection_ack read, or the main loop below); the server may never
+			// react to the "complete" frame on its own.
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	var initPayload json.RawMessage
+	if c.tokenProvider != nil {
+		token, err := c.tokenProvider.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get token: %w", err)
+		}
+		initPayload, err = json.Marshal(map[string]string{"Authorization": "Bearer " + token})
+		if err != nil {
+			return fmt.Errorf("failed to marshal connection_init payload: %w", err)
+		}
+	}
+
+	if err := writeJSON(&wsMessage{Type: msgTypeConnectionInit, Payload: initPayload}); err != nil {
+		return fmt.Errorf("failed to send connection_init: %w", err)
+	}
+
+	var ack wsMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("failed to read connection_ack: %w", err)
+	}
+	if ack.Type != msgTypeConnectionAck {
+		return fmt.Errorf("expected connection_ack, got %q", ack.Type)
+	}
+
+	payload, err := json.Marshal(&subscribePayload{
+		Query:         q,
+		Variables:     v,
+		OperationName: operationName(q),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscribe payload: %w", err)
+	}
+
+	if err := writeJSON(&wsMessage{ID: id, Type: msgTypeSubscribe, Payload: payload}); err != nil {
+		return fmt.Errorf("failed to send subscribe: %w", err)
+	}
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				handler(nil, ctxErr)
+				return ctxErr
+			}
+			readErr := fmt.Errorf("failed to read message: %w", err)
+			handler(nil, readErr)
+			return readErr
+		}
+
+		switch msg.Type {
+		case msgTypePing:
+			if err := writeJSON(&wsMessage{Type: msgTypePong}); err != nil {
+				pongErr := fmt.Errorf("failed to send pong: %w", err)
+				handler(nil, pongErr)
+				return pongErr
+			}
+		case msgTypeNext:
+			resp := &Response{}
+			if err := json.Unmarshal(msg.Payload, resp); err != nil {
+				handler(nil, fmt.Errorf("failed to decode next payload: %w", err))
+				continue
+			}
+			handler(resp, nil)
+		case msgTypeError:
+			var errs []Error
+			if err := json.Unmarshal(msg.Payload, &errs); err != nil {
+				decodeErr := fmt.Errorf("failed to decode error payload: %w", err)
+				handler(nil, decodeErr)
+				return decodeErr
+			}
+			if len(errs) == 0 {
+				handler(nil, ErrSubscriptionComplete)
+				return nil
+			}
+			subErr := Errors(errs)
+			handler(nil, subErr)
+			return subErr
+		case msgTypeComplete:
+			handler(nil, ErrSubscriptionComplete)
+			return nil
+		}
+	}
+}
+
+// wsEndpoint upgrades an http(s) endpoint to its ws(s) equivalent.
+func wsEndpoint(endpoint string) string {
+	switch {
+	case strings.HasPrefix(endpoint, "https://"):
+		return "wss://" + strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "http://"):
+		return "ws://" + strings.TrimPrefix(endpoint, "http://")
+	default:
+		return endpoint
+	}
+}
@@ -0,0 +1,344 @@
+package gqlclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stefanprifti/gqlclient"
+)
+
+func httptestHandler(t *testing.T, upgrader websocket.Upgrader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var init map[string]interface{}
+		if err := conn.ReadJSON(&init); err != nil {
+			t.Errorf("failed to read connection_init: %v", err)
+			return
+		}
+		if err := conn.WriteJSON(map[string]interface{}{"type": "connection_ack"}); err != nil {
+			t.Errorf("failed to write connection_ack: %v", err)
+			return
+		}
+
+		var sub map[string]interface{}
+		if err := conn.ReadJSON(&sub); err != nil {
+			t.Errorf("failed to read subscribe: %v", err)
+			return
+		}
+		if payload, ok := sub["payload"].(map[string]interface{}); ok {
+			if name, _ := payload["operationName"].(string); name != "Channel" {
+				t.Errorf("expected subscribe payload operationName %q, got %q", "Channel", name)
+			}
+		} else {
+			t.Errorf("expected a subscribe payload, got %v", sub["payload"])
+		}
+
+		err = conn.WriteJSON(map[string]interface{}{
+			"id":   sub["id"],
+			"type": "next",
+			"payload": map[string]interface{}{
+				"data": map[string]interface{}{
+					"channel": map[string]interface{}{"message": "hello"},
+				},
+			},
+		})
+		if err != nil {
+			t.Errorf("failed to write next: %v", err)
+			return
+		}
+
+		if err := conn.WriteJSON(map[string]interface{}{"id": sub["id"], "type": "complete"}); err != nil {
+			t.Errorf("failed to write complete: %v", err)
+			return
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"graphql-transport-ws"},
+	}
+
+	srv := httptest.NewServer(httptestHandler(t, upgrader))
+	defer srv.Close()
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: srv.URL,
+	})
+
+	type event struct {
+		Channel struct {
+			Message string `json:"message"`
+		} `json:"channel"`
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	received := make(chan string, 1)
+	var finalErr error
+	calls := 0
+	err := client.Subscribe(ctx, `subscription Channel { channel { message } }`, map[string]interface{}{}, func(resp *gqlclient.Response, err error) {
+		calls++
+		if err != nil {
+			finalErr = err
+			return
+		}
+		var ev event
+		if data, ok := resp.Data.(map[string]interface{}); ok {
+			if ch, ok := data["channel"].(map[string]interface{}); ok {
+				if msg, ok := ch["message"].(string); ok {
+					ev.Channel.Message = msg
+				}
+			}
+		}
+		received <- ev.Channel.Message
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if msg != "hello" {
+			t.Errorf("expected message %q, got %q", "hello", msg)
+		}
+	default:
+		t.Errorf("expected a message to have been delivered")
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected handler to be called twice (next, then final complete), got %d calls", calls)
+	}
+	if finalErr != gqlclient.ErrSubscriptionComplete {
+		t.Errorf("expected final handler call with ErrSubscriptionComplete, got %v", finalErr)
+	}
+}
+
+// TestSubscribe_CompleteDoesNotLeakWatcherGoroutine exercises a subscription that ends via
+// the server's own "complete" frame, with a ctx that is never cancelled by the test (the
+// common case, e.g. a request-scoped or app-lifetime context). The ctx-cancellation watcher
+// goroutine started by Subscribe must exit on its own once Subscribe returns rather than
+// leaking until (or unless) ctx is later cancelled.
+func TestSubscribe_CompleteDoesNotLeakWatcherGoroutine(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"graphql-transport-ws"},
+	}
+
+	srv := httptest.NewServer(httptestHandler(t, upgrader))
+	defer srv.Close()
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: srv.URL,
+	})
+
+	before := runtime.NumGoroutine()
+
+	ctx := context.Background()
+	err := client.Subscribe(ctx, `subscription Channel { channel { message } }`, map[string]interface{}{}, func(resp *gqlclient.Response, err error) {})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("watcher goroutine leaked: %d goroutines before Subscribe, %d after", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestSubscribe_CancelUnblocksAckRead exercises a server that upgrades the connection but
+// never replies to connection_init. Cancelling ctx must unblock the pending
+// conn.ReadJSON(&ack) rather than hanging forever waiting on a connection_ack that never
+// comes.
+func TestSubscribe_CancelUnblocksAckRead(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"graphql-transport-ws"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		// Simulate a server that accepts the upgrade but never replies to
+		// connection_init: block until the test ends.
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: srv.URL,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Subscribe(ctx, `subscription { channel { message } }`, map[string]interface{}{}, func(resp *gqlclient.Response, err error) {})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return within 2s of cancel while waiting on connection_ack")
+	}
+}
+
+// TestSubscribe_CancelUnblocksRead exercises a server that stops responding after
+// "subscribe" (e.g. a dead peer). Cancelling ctx must unblock the goroutine's pending
+// conn.ReadJSON rather than hanging forever waiting on the server to close the connection.
+func TestSubscribe_CancelUnblocksRead(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"graphql-transport-ws"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var init map[string]interface{}
+		if err := conn.ReadJSON(&init); err != nil {
+			return
+		}
+		if err := conn.WriteJSON(map[string]interface{}{"type": "connection_ack"}); err != nil {
+			return
+		}
+
+		var sub map[string]interface{}
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+
+		// Simulate a server that has stopped responding: block until the test ends.
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: srv.URL,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	finalCall := make(chan error, 1)
+	go func() {
+		done <- client.Subscribe(ctx, `subscription { channel { message } }`, map[string]interface{}{}, func(resp *gqlclient.Response, err error) {
+			if err != nil {
+				finalCall <- err
+			}
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return within 2s of cancel")
+	}
+
+	select {
+	case err := <-finalCall:
+		if err != context.Canceled {
+			t.Errorf("expected handler's final call to receive context.Canceled, got %v", err)
+		}
+	default:
+		t.Error("expected handler to be invoked a final time on ctx cancellation")
+	}
+}
+
+// TestSubscribe_ConcurrentPingAndCancel runs under -race to catch concurrent writes to
+// the websocket connection: the server sends a steady stream of pings (answered with
+// pong from the read loop) while the test cancels ctx (sending "complete" from a
+// separate goroutine).
+func TestSubscribe_ConcurrentPingAndCancel(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"graphql-transport-ws"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var init map[string]interface{}
+		if err := conn.ReadJSON(&init); err != nil {
+			return
+		}
+		if err := conn.WriteJSON(map[string]interface{}{"type": "connection_ack"}); err != nil {
+			return
+		}
+
+		var sub map[string]interface{}
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+
+		for i := 0; i < 50; i++ {
+			if err := conn.WriteJSON(map[string]interface{}{"type": "ping"}); err != nil {
+				return
+			}
+			var pong map[string]interface{}
+			if err := conn.ReadJSON(&pong); err != nil {
+				return
+			}
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: srv.URL,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Subscribe(ctx, `subscription { channel { message } }`, map[string]interface{}{}, func(resp *gqlclient.Response, err error) {})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return within 2s of cancel")
+	}
+}
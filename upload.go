@@ -0,0 +1,274 @@
+package gqlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Upload is a placeholder for the GraphQL `Upload` scalar. Use it inside variables passed to
+// QueryWithFiles/MutationWithFiles; its own JSON encoding is never sent (the request carries
+// `null` in its place), it only marks the JSON path that files must provide a reader for,
+// e.g. files["variables.file"] or
+// files["variables.files.0"].
+type Upload struct{}
+
+// QueryWithFiles executes a GraphQL query that uploads files, per the GraphQL multipart
+// request specification (https://github.com/jaydenseric/graphql-multipart-request-spec).
+// files maps the JSON path of each Upload placeholder in v (e.g. "variables.file") to its
+// content. Like Query, it runs through the client's RequestInterceptor chain.
+func (c *Client) QueryWithFiles(ctx context.Context, q string, v interface{}, files map[string]io.Reader, resp interface{}) error {
+	req := &Request{
+		Operation: "query",
+		Query:     q,
+		Variables: v,
+	}
+	return c.doInterceptedMultipart(ctx, req, files, resp)
+}
+
+// MutationWithFiles executes a GraphQL mutation that uploads files. See QueryWithFiles.
+func (c *Client) MutationWithFiles(ctx context.Context, q string, v interface{}, files map[string]io.Reader, resp interface{}) error {
+	req := &Request{
+		Operation: "mutation",
+		Query:     q,
+		Variables: v,
+	}
+	return c.doInterceptedMultipart(ctx, req, files, resp)
+}
+
+// doInterceptedMultipart runs req through the client's interceptor chain, same as do does
+// for Query/Mutation, terminating in doMultipart instead of doTransport.
+func (c *Client) doInterceptedMultipart(ctx context.Context, req *Request, files map[string]io.Reader, data interface{}) error {
+	terminal := func(ctx context.Context, req *Request) (*Response, error) {
+		err := c.doMultipart(ctx, req, files, data)
+		return &Response{Data: data}, err
+	}
+	return c.runIntercepted(ctx, req, terminal)
+}
+
+// doMultipart executes req as a multipart/form-data request carrying files, per the GraphQL
+// multipart request specification.
+func (c *Client) doMultipart(ctx context.Context, req *Request, files map[string]io.Reader, data interface{}) error {
+	if err := validateOperationVariables(req.Variables); err != nil {
+		return fmt.Errorf("failed to validate operation variables: %w", err)
+	}
+
+	variables, paths, err := extractUploadPaths(req.Variables)
+	if err != nil {
+		return fmt.Errorf("failed to extract upload paths: %w", err)
+	}
+
+	if err := validateUploadPaths(files, paths); err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	operations, err := json.Marshal(&Request{
+		Operation: req.Operation,
+		Query:     req.Query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal operations: %w", err)
+	}
+	if err := w.WriteField("operations", string(operations)); err != nil {
+		return fmt.Errorf("failed to write operations part: %w", err)
+	}
+
+	fileMap := make(map[string][]string, len(paths))
+	for i, path := range paths {
+		fileMap[strconv.Itoa(i)] = []string{path}
+	}
+
+	fileMapJSON, err := json.Marshal(fileMap)
+	if err != nil {
+		return fmt.Errorf("failed to marshal file map: %w", err)
+	}
+	if err := w.WriteField("map", string(fileMapJSON)); err != nil {
+		return fmt.Errorf("failed to write map part: %w", err)
+	}
+
+	for i, path := range paths {
+		name := strconv.Itoa(i)
+		part, err := w.CreateFormFile(name, name)
+		if err != nil {
+			return fmt.Errorf("failed to create file part %q: %w", name, err)
+		}
+		if _, err := io.Copy(part, files[path]); err != nil {
+			return fmt.Errorf("failed to write file part %q: %w", name, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	bodyBytes := body.Bytes()
+	contentType := w.FormDataContentType()
+
+	build := func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+		httpReq.Header.Set("Accept", "application/json")
+		if c.token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		for k, v := range req.Headers {
+			httpReq.Header.Set(k, v)
+		}
+		return httpReq, nil
+	}
+
+	return c.doHTTP(ctx, req, build, data)
+}
+
+// uploadType is the reflect.Type of Upload, used to structurally identify Upload
+// placeholders in variables regardless of what they marshal to.
+var uploadType = reflect.TypeOf(Upload{})
+
+// extractUploadPaths walks v and returns a copy with every Upload scalar replaced by nil
+// (so it marshals as the `null` placeholder the spec requires), plus the sorted dot/index
+// path to each Upload it found, e.g. "variables.file" or "variables.files.0".
+func extractUploadPaths(v interface{}) (interface{}, []string, error) {
+	var paths []string
+	collectUploadPaths(reflect.ValueOf(v), "variables", &paths)
+	sort.Strings(paths)
+
+	generic, err := toGenericJSON(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, path := range paths {
+		generic = nilAtPath(generic, strings.Split(path, ".")[1:])
+	}
+
+	return generic, paths, nil
+}
+
+// toGenericJSON round-trips v through JSON so the Upload placeholders collectUploadPaths
+// found can be nilled out regardless of whether v is a struct or a map.
+func toGenericJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal variables: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal variables: %w", err)
+	}
+
+	return generic, nil
+}
+
+// collectUploadPaths recursively walks the actual Go value rv (not its JSON encoding) and
+// records the dot/index path to every field or element whose type is Upload. Walking the
+// real value, rather than guessing from the JSON shape, means a legitimate empty object or
+// map in variables (e.g. `filter: {}`) is never mistaken for an upload placeholder.
+func collectUploadPaths(rv reflect.Value, path string, paths *[]string) {
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return
+	}
+
+	if rv.Type() == uploadType {
+		*paths = append(*paths, path)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			collectUploadPaths(rv.MapIndex(key), fmt.Sprintf("%s.%v", path, key.Interface()), paths)
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			name := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			collectUploadPaths(rv.Field(i), path+"."+name, paths)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			collectUploadPaths(rv.Index(i), fmt.Sprintf("%s.%d", path, i), paths)
+		}
+	}
+}
+
+// jsonFieldName returns the JSON key encoding/json would use for field, honouring a `json`
+// struct tag when present.
+func jsonFieldName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// nilAtPath sets the value at the given path segments (relative to v) to nil and returns
+// the (mutated) root value.
+func nilAtPath(v interface{}, segments []string) interface{} {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		val[segments[0]] = nilAtPath(val[segments[0]], segments[1:])
+		return val
+	case []interface{}:
+		idx, err := strconv.Atoi(segments[0])
+		if err != nil || idx < 0 || idx >= len(val) {
+			return val
+		}
+		val[idx] = nilAtPath(val[idx], segments[1:])
+		return val
+	default:
+		return v
+	}
+}
+
+// validateUploadPaths checks that files provides exactly one reader per Upload placeholder
+// found in variables.
+func validateUploadPaths(files map[string]io.Reader, paths []string) error {
+	if len(files) != len(paths) {
+		return fmt.Errorf("expected %d file(s) to match the Upload placeholder(s) in variables, got %d", len(paths), len(files))
+	}
+
+	for _, path := range paths {
+		if _, ok := files[path]; !ok {
+			return fmt.Errorf("missing file for upload path %q", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package gqlclient_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stefanprifti/gqlclient"
+)
+
+func TestQueryWithFiles(t *testing.T) {
+	query := `mutation Upload($file: Upload!) { uploadFile(file: $file) { id } }`
+	variables := map[string]interface{}{
+		"file": gqlclient.Upload{},
+	}
+	files := map[string]io.Reader{
+		"variables.file": strings.NewReader("file contents"),
+	}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+					if err != nil {
+						t.Fatalf("failed to parse content type: %v", err)
+					}
+
+					mr := multipart.NewReader(req.Body, params["boundary"])
+
+					var operations, fileMap, fileContents string
+					for {
+						part, err := mr.NextPart()
+						if err == io.EOF {
+							break
+						}
+						if err != nil {
+							t.Fatalf("failed to read part: %v", err)
+						}
+
+						b, err := io.ReadAll(part)
+						if err != nil {
+							t.Fatalf("failed to read part %q: %v", part.FormName(), err)
+						}
+
+						switch part.FormName() {
+						case "operations":
+							operations = string(b)
+						case "map":
+							fileMap = string(b)
+						case "0":
+							fileContents = string(b)
+						}
+					}
+
+					if !strings.Contains(operations, `"variables":{"file":null}`) {
+						t.Errorf("expected operations to null out the Upload placeholder, got %s", operations)
+					}
+					if !strings.Contains(fileMap, `"0":["variables.file"]`) {
+						t.Errorf("expected map to associate part 0 with variables.file, got %s", fileMap)
+					}
+					if fileContents != "file contents" {
+						t.Errorf("expected file contents %q, got %q", "file contents", fileContents)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"uploadFile": {"id": "1"}}}`)),
+					}, nil
+				},
+			},
+		},
+	})
+
+	resp := struct {
+		UploadFile struct {
+			ID string `json:"id"`
+		} `json:"uploadFile"`
+	}{}
+
+	if err := client.QueryWithFiles(context.Background(), query, variables, files, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.UploadFile.ID != "1" {
+		t.Errorf("expected id %q, got %q", "1", resp.UploadFile.ID)
+	}
+}
+
+func TestQueryWithFilesIgnoresEmptyObjectVariables(t *testing.T) {
+	query := `mutation Upload($file: Upload!, $filter: FilterInput!) { uploadFile(file: $file, filter: $filter) { id } }`
+	variables := map[string]interface{}{
+		"file":   gqlclient.Upload{},
+		"filter": map[string]interface{}{},
+	}
+	files := map[string]io.Reader{
+		"variables.file": strings.NewReader("file contents"),
+	}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+					if err != nil {
+						t.Fatalf("failed to parse content type: %v", err)
+					}
+
+					mr := multipart.NewReader(req.Body, params["boundary"])
+
+					var operations string
+					for {
+						part, err := mr.NextPart()
+						if err == io.EOF {
+							break
+						}
+						if err != nil {
+							t.Fatalf("failed to read part: %v", err)
+						}
+						if part.FormName() == "operations" {
+							b, err := io.ReadAll(part)
+							if err != nil {
+								t.Fatalf("failed to read operations part: %v", err)
+							}
+							operations = string(b)
+						}
+					}
+
+					if !strings.Contains(operations, `"filter":{}`) {
+						t.Errorf("expected the empty filter object to be preserved as-is, got %s", operations)
+					}
+					if !strings.Contains(operations, `"file":null`) {
+						t.Errorf("expected the Upload placeholder to be nulled out, got %s", operations)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"data": {"uploadFile": {"id": "1"}}}`)),
+					}, nil
+				},
+			},
+		},
+	})
+
+	resp := struct {
+		UploadFile struct {
+			ID string `json:"id"`
+		} `json:"uploadFile"`
+	}{}
+
+	if err := client.QueryWithFiles(context.Background(), query, variables, files, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestQueryWithFilesMismatchedUploads(t *testing.T) {
+	variables := map[string]interface{}{
+		"file": gqlclient.Upload{},
+	}
+
+	client := gqlclient.New(gqlclient.Options{
+		Endpoint: "/query",
+		HTTPClient: &http.Client{
+			Transport: &mockGQLRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					t.Fatal("expected request to fail validation before being sent")
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	resp := struct{}{}
+	err := client.QueryWithFiles(context.Background(), `mutation Upload($file: Upload!) { uploadFile(file: $file) { id } }`, variables, map[string]io.Reader{}, &resp)
+	if err == nil {
+		t.Fatal("expected an error for a missing file, got nil")
+	}
+}